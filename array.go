@@ -1,13 +1,15 @@
 package goavro
 
 import (
+	"encoding/binary"
 	"fmt"
 	"io"
 	"math"
 	"reflect"
+	"strconv"
 )
 
-func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
+func makeArrayCodec(st *symbolTable, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
 	// array type must have items
 	itemSchema, ok := schemaMap["items"]
 	if !ok {
@@ -17,105 +19,233 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 	if err != nil {
 		return nil, fmt.Errorf("Array items ought to be valid Avro type: %s", err)
 	}
+	return newArrayCodec(itemCodec, st.config), nil
+}
+
+// blockEncoder writes the block counts, and when configured, the block sizes,
+// of the binary encoding of an array or map, whose items are encoded between
+// calls to begin and end.
+type blockEncoder struct {
+	itemCount        int64
+	maxBlockCount    int64
+	sized            bool // blocks have a negative count followed by a byte size
+	alreadyEncoded   int64
+	remainingInBlock int64
+	blockCount       int64
+	blockStart       int // offset of the first item of a sized block
+}
+
+// newBlockEncoder returns a blockEncoder for itemCount items, using the block
+// count configured by the WithMaxBlockCount and WithSizedBlocks options.
+func newBlockEncoder(config *codecConfig, itemCount int64) blockEncoder {
+	maxBlockCount := config.blockCountLimit()
+	if config.sizedBlockCount > 0 && config.sizedBlockCount < maxBlockCount {
+		maxBlockCount = config.sizedBlockCount
+	}
+	return blockEncoder{itemCount: itemCount, maxBlockCount: maxBlockCount, sized: config.sizedBlockCount > 0}
+}
+
+// begin appends the header of a new block when the next item starts one.
+func (e *blockEncoder) begin(buf []byte) []byte {
+	if e.remainingInBlock == 0 { // start a new block
+		e.remainingInBlock = e.itemCount - e.alreadyEncoded
+		if e.remainingInBlock > e.maxBlockCount {
+			// limit block count to MacBlockCount
+			e.remainingInBlock = e.maxBlockCount
+		}
+		if e.sized {
+			// NOTE: The byte size of the block is only known once its items
+			// are encoded, so its header is inserted by end.
+			e.blockCount = e.remainingInBlock
+			e.blockStart = len(buf)
+			return buf
+		}
+		buf = appendLong(buf, e.remainingInBlock)
+	}
+	return buf
+}
+
+// end records that an item was appended, and when the item completes a sized
+// block, inserts the negated item count and the byte size of the block before
+// its items.
+func (e *blockEncoder) end(buf []byte) []byte {
+	e.remainingInBlock--
+	e.alreadyEncoded++
+	if !e.sized || e.remainingInBlock > 0 {
+		return buf
+	}
+	return insertBlockHeader(buf, e.blockStart, e.blockCount, true)
+}
 
-	return &Codec{
+// insertBlockHeader inserts the header of the block of count items encoded in
+// buf starting at offset start, which is either the item count, or when sized
+// is true, the negated item count followed by the byte size of the block.
+func insertBlockHeader(buf []byte, start int, count int64, sized bool) []byte {
+	var scratch [2 * binary.MaxVarintLen64]byte
+	var header []byte
+	if sized {
+		header = appendLong(scratch[:0], -count)
+		header = appendLong(header, int64(len(buf)-start))
+	} else {
+		header = appendLong(scratch[:0], count)
+	}
+	buf = append(buf, header...) // grow buf by the length of the header
+	copy(buf[start+len(header):], buf[start:len(buf)-len(header)])
+	copy(buf[start:], header)
+	return buf
+}
+
+// newArrayCodec returns a codec for arrays whose items are translated by
+// itemCodec.
+func newArrayCodec(itemCodec *Codec, config *codecConfig) *Codec {
+	c := &Codec{
 		typeName: &name{"array", nullNamespace},
-		nativeFromBinary: func(buf []byte) (interface{}, []byte, error) {
+		config:   config,
+		binaryInto: func(buf []byte, dst interface{}) (interface{}, []byte, error) {
+			maxBlockCount, maxBlockSize := config.blockCountLimit(), config.blockSizeLimit()
 			var value interface{}
+			var blockCount, blockSize int64
 			var err error
 
 			// block count and block size
-			if value, buf, err = longNativeFromBinary(buf); err != nil {
-				return nil, nil, fmt.Errorf("cannot decode binary array block count: %s", err)
+			if blockCount, buf, err = longFromBinary(buf); err != nil {
+				return nil, nil, fmt.Errorf("cannot decode binary array block count: %w", err)
 			}
-			blockCount := value.(int64)
 			if blockCount < 0 {
 				// NOTE: A negative block count implies there is a long encoded
-				// block size following the negative block count. We have no use
-				// for the block size in this decoder, so we read and discard
-				// the value.
+				// block size following the negative block count. This decoder
+				// only uses the block size to reject blocks larger than
+				// MaxBlockSize.
 				if blockCount == math.MinInt64 {
 					// The minimum number for any signed numerical type can never be made positive
 					return nil, nil, fmt.Errorf("cannot decode binary array with block count: %d", math.MinInt64)
 				}
 				blockCount = -blockCount // convert to its positive equivalent
-				if _, buf, err = longNativeFromBinary(buf); err != nil {
-					return nil, nil, fmt.Errorf("cannot decode binary array block size: %s", err)
+				if blockSize, buf, err = longFromBinary(buf); err != nil {
+					return nil, nil, fmt.Errorf("cannot decode binary array block size: %w", err)
+				}
+				if blockSize > maxBlockSize {
+					return nil, nil, fmt.Errorf("cannot decode binary array when block size exceeds MaxBlockSize: %d > %d", blockSize, maxBlockSize)
 				}
 			}
 			// Ensure block count does not exceed some sane value.
-			if blockCount > MaxBlockCount {
-				return nil, nil, fmt.Errorf("cannot decode binary array when block count exceeds MaxBlockCount: %d > %d", blockCount, MaxBlockCount)
+			if blockCount > maxBlockCount {
+				return nil, nil, fmt.Errorf("cannot decode binary array when block count exceeds MaxBlockCount: %d > %d", blockCount, maxBlockCount)
 			}
 			// NOTE: While the attempt of a RAM optimization shown below is not
 			// necessary, many encoders will encode all items in a single block.
 			// We can optimize amount of RAM allocated by runtime for the array
 			// by initializing the array for that number of items.
-			arrayValues := make([]interface{}, 0, blockCount)
+			previous, _ := dst.([]interface{})
+			arrayValues := previous[:0]
+			if previous == nil {
+				arrayValues = make([]interface{}, 0, blockCount)
+			}
 
 			for blockCount != 0 {
 				// Decode `blockCount` datum values from buffer
 				for i := int64(0); i < blockCount; i++ {
-					if value, buf, err = itemCodec.nativeFromBinary(buf); err != nil {
-						return nil, nil, fmt.Errorf("cannot decode binary array item %d: %s", i+1, err)
+					var item interface{}
+					if len(arrayValues) < len(previous) {
+						item = previous[len(arrayValues)]
+					}
+					if value, buf, err = itemCodec.nativeFromBinaryInto(buf, item); err != nil {
+						return nil, nil, asDecodeError(err, itemCodec).within(strconv.Itoa(len(arrayValues))).prefixed("cannot decode binary array item %d", i+1)
 					}
 					arrayValues = append(arrayValues, value)
 				}
 				// Decode next blockCount from buffer, because there may be more blocks
-				if value, buf, err = longNativeFromBinary(buf); err != nil {
-					return nil, nil, fmt.Errorf("cannot decode binary array block count: %s", err)
+				if blockCount, buf, err = longFromBinary(buf); err != nil {
+					return nil, nil, fmt.Errorf("cannot decode binary array block count: %w", err)
 				}
-				blockCount = value.(int64)
 				if blockCount < 0 {
 					// NOTE: A negative block count implies there is a long
-					// encoded block size following the negative block count. We
-					// have no use for the block size in this decoder, so we
-					// read and discard the value.
+					// encoded block size following the negative block count.
+					// This decoder only uses the block size to reject blocks
+					// larger than MaxBlockSize.
 					if blockCount == math.MinInt64 {
 						// The minimum number for any signed numerical type can
 						// never be made positive
 						return nil, nil, fmt.Errorf("cannot decode binary array with block count: %d", math.MinInt64)
 					}
 					blockCount = -blockCount // convert to its positive equivalent
-					if _, buf, err = longNativeFromBinary(buf); err != nil {
-						return nil, nil, fmt.Errorf("cannot decode binary array block size: %s", err)
+					if blockSize, buf, err = longFromBinary(buf); err != nil {
+						return nil, nil, fmt.Errorf("cannot decode binary array block size: %w", err)
+					}
+					if blockSize > maxBlockSize {
+						return nil, nil, fmt.Errorf("cannot decode binary array when block size exceeds MaxBlockSize: %d > %d", blockSize, maxBlockSize)
 					}
 				}
 				// Ensure block count does not exceed some sane value.
-				if blockCount > MaxBlockCount {
-					return nil, nil, fmt.Errorf("cannot decode binary array when block count exceeds MaxBlockCount: %d > %d", blockCount, MaxBlockCount)
+				if blockCount > maxBlockCount {
+					return nil, nil, fmt.Errorf("cannot decode binary array when block count exceeds MaxBlockCount: %d > %d", blockCount, maxBlockCount)
 				}
 			}
+			for i := len(arrayValues); i < len(previous); i++ {
+				previous[i] = nil // release items of the previous value no longer used
+			}
 			return arrayValues, buf, nil
 		},
+		skipBinary: func(buf []byte) ([]byte, error) {
+			var blockCount, blockSize, index int64
+			var err error
+			for {
+				if blockCount, buf, err = longFromBinary(buf); err != nil {
+					return nil, fmt.Errorf("cannot skip binary array block count: %w", err)
+				}
+				if blockCount == 0 {
+					return buf, nil
+				}
+				if blockCount < 0 {
+					// NOTE: A negative block count implies there is a long
+					// encoded block size following the negative block count,
+					// which allows skipping the entire block at once.
+					if blockCount == math.MinInt64 {
+						// The minimum number for any signed numerical type can
+						// never be made positive
+						return nil, fmt.Errorf("cannot skip binary array with block count: %d", math.MinInt64)
+					}
+					if blockSize, buf, err = longFromBinary(buf); err != nil {
+						return nil, fmt.Errorf("cannot skip binary array block size: %w", err)
+					}
+					if blockSize < 0 || blockSize > int64(len(buf)) {
+						return nil, fmt.Errorf("cannot skip binary array block size: %d: %w", blockSize, io.ErrShortBuffer)
+					}
+					buf = buf[blockSize:]
+					index += blockCount
+					continue
+				}
+				// Ensure block count does not exceed some sane value.
+				if maxBlockCount := config.blockCountLimit(); blockCount > maxBlockCount {
+					return nil, fmt.Errorf("cannot skip binary array when block count exceeds MaxBlockCount: %d > %d", blockCount, maxBlockCount)
+				}
+				for i := int64(0); i < blockCount; i++ {
+					if buf, err = itemCodec.skipBinary(buf); err != nil {
+						return nil, asDecodeError(err, itemCodec).within(strconv.FormatInt(index, 10)).prefixed("cannot skip binary array item %d", i+1)
+					}
+					index++
+				}
+			}
+		},
 		binaryFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
+			if next, ok := itemProducer(datum); ok {
+				return appendProducedItems(nil, buf, itemCodec, config, next)
+			}
 			arrayValues, err := convertArray(datum)
 			if err != nil {
 				return nil, fmt.Errorf("cannot encode binary array: %s", err)
 			}
 
-			arrayLength := int64(len(arrayValues))
-			var alreadyEncoded, remainingInBlock int64
-
+			blocks := newBlockEncoder(config, int64(len(arrayValues)))
 			for i, item := range arrayValues {
-				if remainingInBlock == 0 { // start a new block
-					remainingInBlock = arrayLength - alreadyEncoded
-					if remainingInBlock > MaxBlockCount {
-						// limit block count to MacBlockCount
-						remainingInBlock = MaxBlockCount
-					}
-					buf, _ = longBinaryFromNative(buf, remainingInBlock)
-				}
-
+				buf = blocks.begin(buf)
 				if buf, err = itemCodec.binaryFromNative(buf, item); err != nil {
-					return nil, fmt.Errorf("cannot encode binary array item %d: %v: %s", i+1, item, err)
+					return nil, asEncodeError(err, itemCodec, item).within(strconv.Itoa(i)).prefixed("cannot encode binary array item %d: %v", i+1, item)
 				}
-
-				remainingInBlock--
-				alreadyEncoded++
+				buf = blocks.end(buf)
 			}
 
-			return longBinaryFromNative(buf, 0) // append trailing 0 block count to signal end of Array
+			return append(buf, 0), nil // append trailing 0 block count to signal end of Array
 		},
 		nativeFromTextual: func(buf []byte) (interface{}, []byte, error) {
 			var arrayValues []interface{}
@@ -124,10 +254,10 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 			var b byte
 
 			if buf, err = advanceAndConsume(buf, '['); err != nil {
-				return nil, nil, fmt.Errorf("cannot decode textual array: %s", err)
+				return nil, nil, fmt.Errorf("cannot decode textual array: %w", err)
 			}
 			if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
-				return nil, nil, fmt.Errorf("cannot decode textual array: %s", io.ErrShortBuffer)
+				return nil, nil, fmt.Errorf("cannot decode textual array: %w", io.ErrShortBuffer)
 			}
 			// NOTE: Special case for empty array
 			if buf[0] == ']' {
@@ -139,12 +269,12 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 				// decode value
 				value, buf, err = itemCodec.nativeFromTextual(buf)
 				if err != nil {
-					return nil, nil, fmt.Errorf("cannot decode textual array: %s", err)
+					return nil, nil, asDecodeError(err, itemCodec).within(strconv.Itoa(len(arrayValues))).prefixed("cannot decode textual array")
 				}
 				arrayValues = append(arrayValues, value)
 				// either comma or closing curly brace
 				if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
-					return nil, nil, fmt.Errorf("cannot decode textual array: %s", io.ErrShortBuffer)
+					return nil, nil, fmt.Errorf("cannot decode textual array: %w", io.ErrShortBuffer)
 				}
 				switch b = buf[0]; b {
 				case ']':
@@ -156,7 +286,7 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 				}
 				// NOTE: consume comma from above
 				if buf, _ = advanceToNonWhitespace(buf[1:]); len(buf) == 0 {
-					return nil, nil, fmt.Errorf("cannot decode textual array: %s", io.ErrShortBuffer)
+					return nil, nil, fmt.Errorf("cannot decode textual array: %w", io.ErrShortBuffer)
 				}
 			}
 			return nil, buf, io.ErrShortBuffer
@@ -178,7 +308,7 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 				buf, err = itemCodec.textualFromNative(buf, item)
 				if err != nil {
 					// field was specified in datum; therefore its value was invalid
-					return nil, fmt.Errorf("cannot encode textual array item %d; %v: %s", i+1, item, err)
+					return nil, asEncodeError(err, itemCodec, item).within(strconv.Itoa(i)).prefixed("cannot encode textual array item %d; %v", i+1, item)
 				}
 				buf = append(buf, ',')
 			}
@@ -188,7 +318,12 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 			}
 			return append(buf, ']'), nil
 		},
-	}, nil
+	}
+	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
+		return c.binaryInto(buf, nil)
+	}
+	c.node = &ArraySchema{c: c, items: itemCodec.node}
+	return c
 }
 
 // convertArray converts datum to []interface{} if possible.
@@ -1,7 +1,11 @@
 package goavro_test
 
 import (
+	"bytes"
+	"reflect"
 	"testing"
+
+	"github.com/karrick/goavro"
 )
 
 func TestArraySchema(t *testing.T) {
@@ -127,3 +131,67 @@ func TestArrayTextCodecPass(t *testing.T) {
 	testTextDecodePass(t, schema, datum, []byte(` [ "\u0001\u2318 " , "value2" ]`))
 	testTextCodecPass(t, schema, []interface{}{}, []byte(`[]`)) // empty array
 }
+
+func TestArraySizedBlocks(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"array","items":"int"}`, goavro.WithSizedBlocks(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := []interface{}{int32(1), int32(-200), int32(3)}
+	buf, err := codec.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{
+		3, 6, 2, 0x8f, 0x03, // block of 2 items using 3 bytes
+		1, 2, 6, // block of 1 item using 1 byte
+		0, // end of array
+	}
+	if !bytes.Equal(buf, expected) {
+		t.Fatalf("Actual: %#v; Expected: %#v", buf, expected)
+	}
+	decoded, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, datum) {
+		t.Errorf("Actual: %#v; Expected: %#v", decoded, datum)
+	}
+
+	// empty arrays have no blocks
+	buf, err = codec.BinaryFromNative(nil, []interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{0}; !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", buf, expected)
+	}
+}
+
+func TestArraySizedBlocksSkipped(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[{"name":"a","type":{"type":"array","items":"string"}},{"name":"b","type":"int"}]}`
+	codec, err := goavro.NewCodecWithOptions(schema, goavro.WithSizedBlocks(10), goavro.WithMaxBlockCount(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, map[string]interface{}{"a": []string{"abc", "de"}, "b": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the lower limit of WithMaxBlockCount applies
+	expected := []byte{1, 8, 6, 'a', 'b', 'c', 1, 6, 4, 'd', 'e', 0, 6}
+	if !bytes.Equal(buf, expected) {
+		t.Fatalf("Actual: %#v; Expected: %#v", buf, expected)
+	}
+	projection, err := goavro.NewProjectionCodec(schema, []string{"/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _, err := projection.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := decoded, map[string]interface{}{"b": int32(3)}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
@@ -99,6 +99,24 @@ func benchmarkNativeFromBinaryUsingV5(b *testing.B, avroPath string) {
 	}
 }
 
+func benchmarkNativeFromBinaryIntoUsingV5(b *testing.B, avroPath string) {
+	avroBlob, err := ioutil.ReadFile(avroPath)
+	if err != nil {
+		b.Fatal(err)
+	}
+	nativeData, codec := nativeFromAvroUsingV5(b, avroBlob)
+	binaryData := binaryFromNativeUsingV5(b, codec, nativeData)
+	var datum interface{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, binaryDatum := range binaryData {
+			if datum, _, err = codec.NativeFromBinaryInto(binaryDatum, datum); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
 func benchmarkTextualFromNativeUsingJSONMarshal(b *testing.B, avroPath string) {
 	avroBlob, err := ioutil.ReadFile(avroPath)
 	if err != nil {
@@ -34,6 +34,10 @@ func BenchmarkNativeFromBinaryUsingV5(b *testing.B) {
 	benchmarkNativeFromBinaryUsingV5(b, "fixtures/quickstop-null.avro")
 }
 
+func BenchmarkNativeFromBinaryIntoUsingV5(b *testing.B) {
+	benchmarkNativeFromBinaryIntoUsingV5(b, "fixtures/quickstop-null.avro")
+}
+
 func BenchmarkTextualFromNativeUsingJSONMarshal(b *testing.B) {
 	benchmarkTextualFromNativeUsingJSONMarshal(b, "fixtures/quickstop-null.avro")
 }
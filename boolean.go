@@ -23,6 +23,13 @@ func booleanNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 	}
 }
 
+func booleanSkipBinary(buf []byte) ([]byte, error) {
+	if len(buf) < 1 {
+		return nil, fmt.Errorf("cannot skip binary boolean: %w", io.ErrShortBuffer)
+	}
+	return buf[1:], nil
+}
+
 func booleanBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 	value, ok := datum.(bool)
 	if !ok {
@@ -37,13 +44,13 @@ func booleanBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 
 func booleanNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 	if len(buf) < 4 {
-		return nil, nil, fmt.Errorf("cannot decode textual boolean: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual boolean: %w", io.ErrShortBuffer)
 	}
 	if bytes.Equal(buf[:4], []byte("true")) {
 		return true, buf[4:], nil
 	}
 	if len(buf) < 5 {
-		return nil, nil, fmt.Errorf("cannot decode textual boolean: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual boolean: %w", io.ErrShortBuffer)
 	}
 	if bytes.Equal(buf[:5], []byte("false")) {
 		return false, buf[5:], nil
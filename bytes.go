@@ -8,37 +8,103 @@ import (
 	"unicode"
 	"unicode/utf16"
 	"unicode/utf8"
+	"unsafe"
 )
 
 ////////////////////////////////////////
 // Binary Decode
 ////////////////////////////////////////
 
-func bytesNativeFromBinary(buf []byte) (interface{}, []byte, error) {
-	if len(buf) < 1 {
-		return nil, nil, fmt.Errorf("cannot decode binary bytes: %s", io.ErrShortBuffer)
-	}
-	var decoded interface{}
-	var err error
-	if decoded, buf, err = longNativeFromBinary(buf); err != nil {
-		return nil, nil, fmt.Errorf("cannot decode binary bytes: %s", err)
+// bytesFromBinary decodes a byte slice without boxing it in an interface, and
+// returns a slice of buf.
+func bytesFromBinary(buf []byte) ([]byte, []byte, error) {
+	size, buf, err := longFromBinary(buf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot decode binary bytes: %w", err)
 	}
-	size := decoded.(int64) // always returns int64
 	if size < 0 {
 		return nil, nil, fmt.Errorf("cannot decode binary bytes: negative size: %d", size)
 	}
 	if size > int64(len(buf)) {
-		return nil, nil, fmt.Errorf("cannot decode binary bytes: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode binary bytes: %w", io.ErrShortBuffer)
 	}
 	return buf[:size], buf[size:], nil
 }
 
+// stringFromBinary decodes a string without boxing it in an interface, and is
+// used directly to decode map keys.
+func stringFromBinary(buf []byte) (string, []byte, error) {
+	someBytes, buf, err := bytesFromBinary(buf)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot decode binary string: %w", err)
+	}
+	return string(someBytes), buf, nil
+}
+
+// stringFromBinaryZeroCopy decodes a string like stringFromBinary, but returns
+// a string that refers to the bytes of buf rather than to a copy of them.
+func stringFromBinaryZeroCopy(buf []byte) (string, []byte, error) {
+	someBytes, buf, err := bytesFromBinary(buf)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot decode binary string: %w", err)
+	}
+	return *(*string)(unsafe.Pointer(&someBytes)), buf, nil
+}
+
+func bytesNativeFromBinary(buf []byte) (interface{}, []byte, error) {
+	someBytes, buf, err := bytesFromBinary(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return someBytes, buf, nil
+}
+
+// bytesNativeFromBinaryCopy decodes bytes like bytesNativeFromBinary, but
+// returns a copy of them rather than a slice of buf.
+func bytesNativeFromBinaryCopy(buf []byte) (interface{}, []byte, error) {
+	someBytes, buf, err := bytesFromBinary(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append([]byte(nil), someBytes...), buf, nil
+}
+
 func stringNativeFromBinary(buf []byte) (interface{}, []byte, error) {
-	d, b, err := bytesNativeFromBinary(buf)
+	someString, buf, err := stringFromBinary(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return someString, buf, nil
+}
+
+func stringNativeFromBinaryZeroCopy(buf []byte) (interface{}, []byte, error) {
+	someString, buf, err := stringFromBinaryZeroCopy(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return someString, buf, nil
+}
+
+func bytesSkipBinary(buf []byte) ([]byte, error) {
+	size, buf, err := longFromBinary(buf)
+	if err != nil {
+		return nil, fmt.Errorf("cannot skip binary bytes: %w", err)
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("cannot skip binary bytes: negative size: %d", size)
+	}
+	if size > int64(len(buf)) {
+		return nil, fmt.Errorf("cannot skip binary bytes: %w", io.ErrShortBuffer)
+	}
+	return buf[size:], nil
+}
+
+func stringSkipBinary(buf []byte) ([]byte, error) {
+	b, err := bytesSkipBinary(buf)
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot decode binary string: %s", err)
+		return nil, fmt.Errorf("cannot skip binary string: %w", err)
 	}
-	return string(d.([]byte)), b, nil
+	return b, nil
 }
 
 ////////////////////////////////////////
@@ -50,8 +116,8 @@ func bytesBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 	if !ok {
 		return nil, fmt.Errorf("cannot encode binary bytes: expected: []byte; received: %T", datum)
 	}
-	buf, _ = longBinaryFromNative(buf, len(someBytes)) // only fails when given non integer
-	return append(buf, someBytes...), nil              // append datum bytes
+	buf = appendLong(buf, int64(len(someBytes)))
+	return append(buf, someBytes...), nil // append datum bytes
 }
 
 func stringBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
@@ -59,8 +125,8 @@ func stringBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 	if !ok {
 		return nil, fmt.Errorf("cannot encode binary bytes: expected: string; received: %T", datum)
 	}
-	buf, _ = longBinaryFromNative(buf, len(someBytes)) // only fails when given non integer
-	return append(buf, someBytes...), nil              // append datum bytes
+	buf = appendLong(buf, int64(len(someBytes)))
+	return append(buf, someBytes...), nil // append datum bytes
 }
 
 ////////////////////////////////////////
@@ -70,7 +136,7 @@ func stringBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 func bytesNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 	buflen := len(buf)
 	if buflen < 2 {
-		return nil, nil, fmt.Errorf("cannot decode textual bytes: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual bytes: %w", io.ErrShortBuffer)
 	}
 	if buf[0] != '"' {
 		return nil, nil, fmt.Errorf("cannot decode textual bytes: expected initial \"; found: %#U", buf[0])
@@ -93,7 +159,7 @@ func bytesNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 				// subtract another 1 because already consumed u but have yet to
 				// increment i.
 				if i > buflen-6 {
-					return nil, nil, fmt.Errorf("cannot decode textual bytes: %s", io.ErrShortBuffer)
+					return nil, nil, fmt.Errorf("cannot decode textual bytes: %w", io.ErrShortBuffer)
 				}
 				// NOTE: Avro bytes represent binary data, and do not
 				// necessarily represent text. Therefore, Avro bytes are not
@@ -101,7 +167,7 @@ func bytesNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 				// digits, the first and second of which must be 0.
 				v, err := parseUint64FromHexSlice(buf[i+3 : i+5])
 				if err != nil {
-					return nil, nil, fmt.Errorf("cannot decode textual bytes: %s", err)
+					return nil, nil, fmt.Errorf("cannot decode textual bytes: %w", err)
 				}
 				i += 4 // absorb 4 characters: one 'u' and three of the digits
 				newBytes = append(newBytes, byte(v))
@@ -125,7 +191,7 @@ func bytesNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 func stringNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 	buflen := len(buf)
 	if buflen < 2 {
-		return nil, nil, fmt.Errorf("cannot decode textual string: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual string: %w", io.ErrShortBuffer)
 	}
 	if buf[0] != '"' {
 		return nil, nil, fmt.Errorf("cannot decode textual string: expected initial \"; found: %#U", buf[0])
@@ -148,11 +214,11 @@ func stringNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 				// subtract another 1 because already consumed u but have yet to
 				// increment i.
 				if i > buflen-6 {
-					return nil, nil, fmt.Errorf("cannot decode textual string: %s", io.ErrShortBuffer)
+					return nil, nil, fmt.Errorf("cannot decode textual string: %w", io.ErrShortBuffer)
 				}
 				v, err := parseUint64FromHexSlice(buf[i+1 : i+5])
 				if err != nil {
-					return nil, nil, fmt.Errorf("cannot decode textual string: %s", err)
+					return nil, nil, fmt.Errorf("cannot decode textual string: %w", err)
 				}
 				i += 4 // absorb 4 characters: one 'u' and three of the digits
 
@@ -170,7 +236,7 @@ func stringNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 
 					v, err = parseUint64FromHexSlice(buf[i+2 : i+6])
 					if err != nil {
-						return nil, nil, fmt.Errorf("cannot decode textual string: %s", err)
+						return nil, nil, fmt.Errorf("cannot decode textual string: %w", err)
 					}
 					i += 5 // absorb 5 characters: two for '\u', and 3 of the 4 digits
 
@@ -1,7 +1,10 @@
 package goavro_test
 
 import (
+	"bytes"
 	"testing"
+
+	"github.com/karrick/goavro"
 )
 
 func TestSchemaPrimitiveCodecBytes(t *testing.T) {
@@ -107,3 +110,58 @@ func TestPrimitiveStringText(t *testing.T) {
 	testTextDecodeFail(t, "string", []byte("\"\\uD83D\\uDE\""), "surrogate pair")
 	testTextDecodeFail(t, "string", []byte("\"\\uD83D\\uDE0\""), "invalid byte")
 }
+
+func TestWithZeroCopy(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"map","values":"string"}`, goavro.WithZeroCopy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := []byte{0x2, 0x2, 'k', 0x6, 'a', 'b', 'c', 0}
+	datum, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := datum.(map[string]interface{})["k"], "abc"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	// NOTE: Modifying the buffer modifies the strings decoded from it.
+	copy(buf[4:], "xyz")
+	if actual, expected := datum.(map[string]interface{})["k"], "xyz"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
+
+func TestWithCopyBytes(t *testing.T) {
+	schema := `{"type":"record","name":"r","fields":[{"name":"b","type":"bytes"},{"name":"f","type":{"type":"fixed","name":"f2","size":2}}]}`
+	buf := []byte{0x4, 'a', 'b', 'c', 'd'}
+
+	for _, copyBytes := range []bool{false, true} {
+		var options []goavro.CodecOption
+		if copyBytes {
+			options = append(options, goavro.WithCopyBytes())
+		}
+		codec, err := goavro.NewCodecWithOptions(schema, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := append([]byte(nil), buf...)
+		datum, _, err := codec.NativeFromBinary(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		copy(input[1:], "wxyz")
+
+		record := datum.(map[string]interface{})
+		expectedBytes, expectedFixed := []byte("wx"), []byte("yz") // slices of the modified buffer
+		if copyBytes {
+			expectedBytes, expectedFixed = []byte("ab"), []byte("cd")
+		}
+		if actual := record["b"].([]byte); !bytes.Equal(actual, expectedBytes) {
+			t.Errorf("copy: %t; Actual: %q; Expected: %q", copyBytes, actual, expectedBytes)
+		}
+		if actual := record["f"].([]byte); !bytes.Equal(actual, expectedFixed) {
+			t.Errorf("copy: %t; Actual: %q; Expected: %q", copyBytes, actual, expectedFixed)
+		}
+	}
+}
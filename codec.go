@@ -10,21 +10,36 @@ import (
 // Codec is created as a stateless structure that can be safely used in multiple
 // go routines simultaneously.
 type Codec struct {
-	typeName *name
-	schema   string
+	typeName   *name
+	schema     string
+	node       Schema
+	namedTypes map[string]Schema // named types defined by the schema, by full name
+	config     *codecConfig      // options used to build the codec
 
 	nativeFromTextual func([]byte) (interface{}, []byte, error)
 	binaryFromNative  func([]byte, interface{}) ([]byte, error)
 	nativeFromBinary  func([]byte) (interface{}, []byte, error)
+	binaryInto        func([]byte, interface{}) (interface{}, []byte, error) // optional: reuses maps and slices of its second argument
+	skipBinary        func([]byte) ([]byte, error)
 	textualFromNative func([]byte, interface{}) ([]byte, error)
 }
 
-func newSymbolTable() map[string]*Codec {
-	return map[string]*Codec{
+// symbolTable holds the codecs of the types known while building the codec for
+// a schema, along with the configuration used to build them.
+type symbolTable struct {
+	codecs      map[string]*Codec
+	config      *codecConfig
+	definitions map[string]typeDefinition // named types defined while building, by full name
+	registered  int                       // depth of registered definitions being built
+}
+
+func newSymbolTable(config *codecConfig) *symbolTable {
+	codecs := map[string]*Codec{
 		"boolean": &Codec{
 			typeName:          &name{"boolean", nullNamespace},
 			binaryFromNative:  booleanBinaryFromNative,
 			nativeFromBinary:  booleanNativeFromBinary,
+			skipBinary:        booleanSkipBinary,
 			nativeFromTextual: booleanNativeFromTextual,
 			textualFromNative: booleanTextualFromNative,
 		},
@@ -32,6 +47,7 @@ func newSymbolTable() map[string]*Codec {
 			typeName:          &name{"bytes", nullNamespace},
 			binaryFromNative:  bytesBinaryFromNative,
 			nativeFromBinary:  bytesNativeFromBinary,
+			skipBinary:        bytesSkipBinary,
 			nativeFromTextual: bytesNativeFromTextual,
 			textualFromNative: bytesTextualFromNative,
 		},
@@ -39,6 +55,7 @@ func newSymbolTable() map[string]*Codec {
 			typeName:          &name{"double", nullNamespace},
 			binaryFromNative:  doubleBinaryFromNative,
 			nativeFromBinary:  doubleNativeFromBinary,
+			skipBinary:        doubleSkipBinary,
 			nativeFromTextual: doubleNativeFromTextual,
 			textualFromNative: doubleTextualFromNative,
 		},
@@ -46,6 +63,7 @@ func newSymbolTable() map[string]*Codec {
 			typeName:          &name{"float", nullNamespace},
 			binaryFromNative:  floatBinaryFromNative,
 			nativeFromBinary:  floatNativeFromBinary,
+			skipBinary:        floatSkipBinary,
 			nativeFromTextual: floatNativeFromTextual,
 			textualFromNative: floatTextualFromNative,
 		},
@@ -53,6 +71,7 @@ func newSymbolTable() map[string]*Codec {
 			typeName:          &name{"int", nullNamespace},
 			binaryFromNative:  intBinaryFromNative,
 			nativeFromBinary:  intNativeFromBinary,
+			skipBinary:        intSkipBinary,
 			nativeFromTextual: intNativeFromTextual,
 			textualFromNative: intTextualFromNative,
 		},
@@ -60,6 +79,7 @@ func newSymbolTable() map[string]*Codec {
 			typeName:          &name{"long", nullNamespace},
 			binaryFromNative:  longBinaryFromNative,
 			nativeFromBinary:  longNativeFromBinary,
+			skipBinary:        longSkipBinary,
 			nativeFromTextual: longNativeFromTextual,
 			textualFromNative: longTextualFromNative,
 		},
@@ -67,6 +87,7 @@ func newSymbolTable() map[string]*Codec {
 			typeName:          &name{"null", nullNamespace},
 			binaryFromNative:  nullBinaryFromNative,
 			nativeFromBinary:  nullNativeFromBinary,
+			skipBinary:        nullSkipBinary,
 			nativeFromTextual: nullNativeFromTextual,
 			textualFromNative: nullTextualFromNative,
 		},
@@ -74,10 +95,22 @@ func newSymbolTable() map[string]*Codec {
 			typeName:          &name{"string", nullNamespace},
 			binaryFromNative:  stringBinaryFromNative,
 			nativeFromBinary:  stringNativeFromBinary,
+			skipBinary:        stringSkipBinary,
 			nativeFromTextual: stringNativeFromTextual,
 			textualFromNative: stringTextualFromNative,
 		},
 	}
+	for typeName, c := range codecs {
+		c.node = &PrimitiveSchema{c: c, typeName: typeName}
+		c.config = config
+	}
+	if config.zeroCopy {
+		codecs["string"].nativeFromBinary = stringNativeFromBinaryZeroCopy
+	}
+	if config.copyBytes {
+		codecs["bytes"].nativeFromBinary = bytesNativeFromBinaryCopy
+	}
+	return &symbolTable{codecs: codecs, config: config, definitions: make(map[string]typeDefinition)}
 }
 
 // NewCodec returns a Codec used to translate between a byte slice of either
@@ -90,9 +123,10 @@ func newSymbolTable() map[string]*Codec {
 //
 // A particular `Codec` can work with only one Avro schema. However,
 // there is no practical limit to how many `Codec`s may be created and
-// used in a program. Internally a `Codec` is merely a named tuple of
-// four function pointers, and maintains no runtime state that is mutated
-// after instantiation. In other words, `Codec`s may be safely used by
+// used in a program. Internally a `Codec` is merely a named tuple of the
+// functions that encode, decode, and skip its data, along with its schema
+// and the options used to build it, and maintains no runtime state that is
+// mutated after instantiation. In other words, `Codec`s may be safely used by
 // many go routines simultaneously, as your program requires.
 //
 //     codec, err := goavro.NewCodec(`
@@ -108,34 +142,66 @@ func newSymbolTable() map[string]*Codec {
 //             fmt.Println(err)
 //     }
 func NewCodec(schemaSpecification string) (*Codec, error) {
+	return NewCodecWithOptions(schemaSpecification)
+}
+
+// NewCodecWithOptions returns a Codec like NewCodec does, configured by the
+// provided options. Options only affect the Codec being created, so Codecs
+// configured with different options may be used in the same program.
+//
+//     codec, err := goavro.NewCodecWithOptions(schema, goavro.WithSchemaHook(func(node goavro.Annotated) error {
+//             if field, ok := node.(*goavro.FieldSchema); ok {
+//                     if pii, _ := field.Property("pii"); pii == true {
+//                             fmt.Println("PII field:", field.Record().Name(), field.Name())
+//                     }
+//             }
+//             return nil
+//     }))
+//     if err != nil {
+//             fmt.Println(err)
+//     }
+func NewCodecWithOptions(schemaSpecification string, options ...CodecOption) (*Codec, error) {
+	config := new(codecConfig)
+	for _, option := range options {
+		option(config)
+	}
+	c, _, err := newCodec(schemaSpecification, config)
+	return c, err
+}
+
+// newCodec returns a Codec for the schema, along with the symbol table used to
+// build it.
+func newCodec(schemaSpecification string, config *codecConfig) (*Codec, *symbolTable, error) {
 	// bootstrap a symbol table with primitive type codecs for the new codec
-	st := newSymbolTable()
+	st := newSymbolTable(config)
 
 	// NOTE: Some clients might give us unadorned primitive type name for the
 	// schema, e.g., "long". While it is not valid JSON, it is a valid schema.
 	// Provide special handling for primitive type names.
-	if c, ok := st[schemaSpecification]; ok {
+	if c, ok := st.codecs[schemaSpecification]; ok {
 		c.schema = schemaSpecification
-		return c, nil
+		return c, st, nil
 	}
 
 	// NOTE: At this point, schema should be valid JSON, otherwise it's an error
 	// condition.
 	var schema interface{}
 	if err := json.Unmarshal([]byte(schemaSpecification), &schema); err != nil {
-		return nil, fmt.Errorf("cannot unmarshal schema JSON: %s", err)
+		return nil, nil, &SchemaError{Err: fmt.Errorf("cannot unmarshal schema JSON: %w", err)}
 	}
 
 	c, err := buildCodec(st, nullNamespace, schema)
-	if err == nil {
-		// compact schema and save it
-		compact, err := json.Marshal(schema)
-		if err != nil {
-			return nil, fmt.Errorf("cannot remarshal schema: %s", err)
-		}
-		c.schema = string(compact)
+	if err != nil {
+		return nil, nil, &SchemaError{Err: err}
 	}
-	return c, err
+	// compact schema and save it
+	compact, err := json.Marshal(schema)
+	if err != nil {
+		return nil, nil, &SchemaError{Err: fmt.Errorf("cannot remarshal schema: %w", err)}
+	}
+	c.schema = string(compact)
+	c.namedTypes = namedTypes(st)
+	return c, st, nil
 }
 
 // BinaryFromNative appends the binary encoded byte slice representation of the
@@ -182,7 +248,7 @@ func NewCodec(schemaSpecification string) (*Codec, error) {
 func (c *Codec) BinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 	newBuf, err := c.binaryFromNative(buf, datum)
 	if err != nil {
-		return buf, err // if error, return original byte slice
+		return buf, asEncodeError(err, c, datum) // if error, return original byte slice
 	}
 	return newBuf, nil
 }
@@ -219,13 +285,82 @@ func (c *Codec) BinaryFromNative(buf []byte, datum interface{}) ([]byte, error)
 //         // Output: map[next:map[LongList:map[next:map[LongList:map[next:<nil>]]]]]
 //     }
 func (c *Codec) NativeFromBinary(buf []byte) (interface{}, []byte, error) {
+	if c.config != nil && c.config.hasDecodingLimits() {
+		if err := checkDecodingLimits(c.config, c.node, buf); err != nil {
+			return nil, buf, asDecodeError(err, c)
+		}
+	}
 	value, newBuf, err := c.nativeFromBinary(buf)
 	if err != nil {
-		return nil, buf, err // if error, return original byte slice
+		return nil, buf, asDecodeError(err, c) // if error, return original byte slice
 	}
 	return value, newBuf, nil
 }
 
+// NativeFromBinaryInto converts Avro data in binary format from the provided
+// byte slice to Go native data types like NativeFromBinary does, but reuses the
+// maps and slices of dst, a value previously returned by this Codec, rather
+// than allocating new ones. Maps of records and maps are cleared and refilled,
+// slices of arrays are truncated and refilled, and the values they hold are
+// themselves reused when possible. When dst is nil, or holds data of a
+// different shape, new maps and slices are allocated, as NativeFromBinary does.
+//
+// The returned value, which is dst itself when it was reused, ought to be used
+// in place of dst, and ought to be provided as dst for the next datum. On
+// error, the contents of dst are undefined.
+//
+//     var datum interface{}
+//     for len(buf) > 0 {
+//             datum, buf, err = codec.NativeFromBinaryInto(buf, datum)
+//             if err != nil {
+//                     return err
+//             }
+//             process(datum) // ought not retain datum or its maps and slices
+//     }
+func (c *Codec) NativeFromBinaryInto(buf []byte, dst interface{}) (interface{}, []byte, error) {
+	if c.config != nil && c.config.hasDecodingLimits() {
+		if err := checkDecodingLimits(c.config, c.node, buf); err != nil {
+			return nil, buf, asDecodeError(err, c)
+		}
+	}
+	value, newBuf, err := c.nativeFromBinaryInto(buf, dst)
+	if err != nil {
+		return nil, buf, asDecodeError(err, c) // if error, return original byte slice
+	}
+	return value, newBuf, nil
+}
+
+// nativeFromBinaryInto decodes a binary datum, reusing the maps and slices of
+// dst when the codec supports it.
+func (c *Codec) nativeFromBinaryInto(buf []byte, dst interface{}) (interface{}, []byte, error) {
+	if c.binaryInto != nil && dst != nil {
+		return c.binaryInto(buf, dst)
+	}
+	return c.nativeFromBinary(buf)
+}
+
+// SkipBinary advances past one datum in the binary encoded byte slice in
+// accordance with the Avro schema supplied when creating the Codec, without
+// decoding it into native Go data, and without allocating memory. On success,
+// it returns a new byte slice with the datum's bytes consumed, and a nil error
+// value. On error, it returns the original byte slice, and the error message.
+// It may be used to count, sample, or index data, or to validate its framing.
+//
+//     var count int
+//     for len(buf) > 0 {
+//         if buf, err = codec.SkipBinary(buf); err != nil {
+//             return err
+//         }
+//         count++
+//     }
+func (c *Codec) SkipBinary(buf []byte) ([]byte, error) {
+	newBuf, err := c.skipBinary(buf)
+	if err != nil {
+		return buf, asDecodeError(err, c) // if error, return original byte slice
+	}
+	return newBuf, nil
+}
+
 // NativeFromTextual converts Avro data in JSON text format from the provided byte
 // slice to Go native data types in accordance with the Avro schema supplied
 // when creating the Codec. On success, it returns the decoded datum, along with
@@ -261,7 +396,7 @@ func (c *Codec) NativeFromBinary(buf []byte) (interface{}, []byte, error) {
 func (c *Codec) NativeFromTextual(buf []byte) (interface{}, []byte, error) {
 	value, newBuf, err := c.nativeFromTextual(buf)
 	if err != nil {
-		return nil, buf, err // if error, return original byte slice
+		return nil, buf, asDecodeError(err, c) // if error, return original byte slice
 	}
 	return value, newBuf, nil
 }
@@ -309,7 +444,7 @@ func (c *Codec) NativeFromTextual(buf []byte) (interface{}, []byte, error) {
 func (c *Codec) TextualFromNative(buf []byte, datum interface{}) ([]byte, error) {
 	newBuf, err := c.textualFromNative(buf, datum)
 	if err != nil {
-		return buf, err // if error, return original byte slice
+		return buf, asEncodeError(err, c, datum) // if error, return original byte slice
 	}
 	return newBuf, nil
 }
@@ -331,7 +466,7 @@ func (c *Codec) Schema() string {
 
 // convert a schema data structure to a codec, prefixing with specified
 // namespace
-func buildCodec(st map[string]*Codec, enclosingNamespace string, schema interface{}) (*Codec, error) {
+func buildCodec(st *symbolTable, enclosingNamespace string, schema interface{}) (*Codec, error) {
 	switch schemaType := schema.(type) {
 	case map[string]interface{}:
 		return buildCodecForTypeDescribedByMap(st, enclosingNamespace, schemaType)
@@ -345,7 +480,7 @@ func buildCodec(st map[string]*Codec, enclosingNamespace string, schema interfac
 }
 
 // Reach into the map, grabbing its "type". Use that to create the codec.
-func buildCodecForTypeDescribedByMap(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
+func buildCodecForTypeDescribedByMap(st *symbolTable, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
 	t, ok := schemaMap["type"]
 	if !ok {
 		return nil, fmt.Errorf("missing type: %v", schemaMap)
@@ -369,19 +504,59 @@ func buildCodecForTypeDescribedByMap(st map[string]*Codec, enclosingNamespace st
 	}
 }
 
-func buildCodecForTypeDescribedByString(st map[string]*Codec, enclosingNamespace string, typeName string, schemaMap map[string]interface{}) (*Codec, error) {
+func buildCodecForTypeDescribedByString(st *symbolTable, enclosingNamespace string, typeName string, schemaMap map[string]interface{}) (*Codec, error) {
 	// NOTE: When codec already exists, return it. This includes both primitive
 	// type codecs added in NewCodec, and user-defined types, added while
-	// building the codec.
-	if cd, ok := st[typeName]; ok {
-		return cd, nil
+	// building the codec. Primitive type names have no namespace, while other
+	// names without periods abbreviate a name in the enclosing namespace, or
+	// else in the null namespace.
+	if cd, ok := st.codecs[typeName]; ok {
+		if _, ok = cd.node.(*PrimitiveSchema); ok {
+			if st.config.logicalTypes && schemaMap != nil {
+				if logicalType, ok := schemaMap["logicalType"].(string); ok {
+					if lc := makeLogicalTypeCodec(cd, logicalType); lc != nil {
+						return lc, nil
+					}
+				}
+			}
+			return cd, nil
+		}
+	}
+	// NOTE: Within a schema object, the names of complex types are keywords,
+	// even when a named type with the same simple name, such as "x.record", is
+	// in scope.
+	if _, ok := complexTypeNames[typeName]; ok && schemaMap != nil {
+		return buildCodecForComplexType(st, enclosingNamespace, typeName, schemaMap)
 	}
-	// NOTE: Sometimes schema may abbreviate type name inside a namespace.
-	if enclosingNamespace != "" {
-		if cd, ok := st[enclosingNamespace+"."+typeName]; ok {
+	for _, fullName := range candidateFullNames(typeName, enclosingNamespace) {
+		if cd, ok := st.codecs[fullName]; ok {
 			return cd, nil
 		}
 	}
+	// NOTE: Types defined by other schemas may be resolved using a registry.
+	if st.config.registry != nil {
+		if definition, ok := st.config.registry.lookup(typeName, enclosingNamespace); ok {
+			st.registered++
+			defer func() { st.registered-- }()
+			return buildCodec(st, definition.namespace, definition.schemaMap)
+		}
+	}
+	return buildCodecForComplexType(st, enclosingNamespace, typeName, schemaMap)
+}
+
+// buildCodecForComplexType returns a codec for the complex type described by
+// schemaMap, or an error when typeName does not name a complex type.
+func buildCodecForComplexType(st *symbolTable, enclosingNamespace string, typeName string, schemaMap map[string]interface{}) (*Codec, error) {
+	// NOTE: A registered definition may define inline a named type that was
+	// already built from another registered definition. The registry ensures
+	// both definitions are identical, so the type is not defined again.
+	if st.registered > 0 && (typeName == "enum" || typeName == "fixed" || typeName == "record") {
+		if n, err := newNameFromSchemaMap(enclosingNamespace, schemaMap); err == nil {
+			if cd, ok := st.codecs[n.fullName]; ok {
+				return cd, nil
+			}
+		}
+	}
 	// There are only a small handful of complex Avro data types.
 	switch typeName {
 	case "array":
@@ -401,19 +576,42 @@ func buildCodecForTypeDescribedByString(st map[string]*Codec, enclosingNamespace
 
 // notion of enclosing namespace changes when record, enum, or fixed create a
 // new namespace, for child objects.
-func registerNewCodec(st map[string]*Codec, schemaMap map[string]interface{}, enclosingNamespace string) (*Codec, error) {
+func registerNewCodec(st *symbolTable, schemaMap map[string]interface{}, enclosingNamespace string) (*Codec, error) {
 	n, err := newNameFromSchemaMap(enclosingNamespace, schemaMap)
 	if err != nil {
 		return nil, err
 	}
-	c := &Codec{typeName: n}
-	st[n.fullName] = c
+	if err = checkTypeName(n); err != nil {
+		return nil, err
+	}
+	if _, ok := st.codecs[n.fullName]; ok {
+		return nil, fmt.Errorf("schema ought not redefine type: %q", n.fullName)
+	}
+	if st.config.registry != nil {
+		if err = st.config.registry.checkDefinition(n.fullName, schemaMap); err != nil {
+			return nil, err
+		}
+	}
+	c := &Codec{typeName: n, config: st.config}
+	st.codecs[n.fullName] = c
+	st.definitions[n.fullName] = typeDefinition{schemaMap: schemaMap, namespace: n.namespace}
 	return c, nil
 }
 
-func typeNames(st map[string]*Codec) []string {
+// namedTypes returns the nodes describing the named types in the symbol table.
+func namedTypes(st *symbolTable) map[string]Schema {
+	named := make(map[string]Schema)
+	for fullName, c := range st.codecs {
+		if _, ok := c.node.(*PrimitiveSchema); !ok {
+			named[fullName] = c.node
+		}
+	}
+	return named
+}
+
+func typeNames(st *symbolTable) []string {
 	var keys []string
-	for k := range st {
+	for k := range st.codecs {
 		keys = append(keys, k)
 	}
 	return keys
@@ -0,0 +1,304 @@
+package goavro_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+const skipTestSchema = `
+{"type": "record", "name": "Everything",
+ "fields": [
+     {"name": "n", "type": "null"},
+     {"name": "b", "type": "boolean"},
+     {"name": "i", "type": "int"},
+     {"name": "l", "type": "long"},
+     {"name": "f", "type": "float"},
+     {"name": "d", "type": "double"},
+     {"name": "by", "type": "bytes"},
+     {"name": "s", "type": "string"},
+     {"name": "e", "type": {"type": "enum", "name": "Suit", "symbols": ["SPADES", "HEARTS"]}},
+     {"name": "x", "type": {"type": "fixed", "name": "Four", "size": 4}},
+     {"name": "a", "type": {"type": "array", "items": "long"}},
+     {"name": "m", "type": {"type": "map", "values": "string"}},
+     {"name": "u", "type": ["null", "string", "Suit"]}
+ ]
+}`
+
+func newSkipTestBinary(tb testing.TB) (*goavro.Codec, []byte) {
+	codec, err := goavro.NewCodec(skipTestSchema)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, map[string]interface{}{
+		"n":  nil,
+		"b":  true,
+		"i":  int32(-123456),
+		"l":  int64(1) << 40,
+		"f":  float32(3.5),
+		"d":  float64(-2.25),
+		"by": []byte("some bytes"),
+		"s":  "some string",
+		"e":  "HEARTS",
+		"x":  []byte("abcd"),
+		"a":  []interface{}{int64(1), int64(1000), int64(-1000000)},
+		"m":  map[string]interface{}{"k1": "v1", "k2": "v2"},
+		"u":  goavro.Union("Suit", "SPADES"),
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return codec, buf
+}
+
+func TestCodecSkipBinary(t *testing.T) {
+	codec, buf := newSkipTestBinary(t)
+	buf = append(buf, buf...) // two data items back to back
+
+	var count int
+	for len(buf) > 0 {
+		var err error
+		if buf, err = codec.SkipBinary(buf); err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	if actual, expected := count, 2; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestCodecSkipBinaryErrors(t *testing.T) {
+	codec, buf := newSkipTestBinary(t)
+	for i := 0; i < len(buf); i++ {
+		short := buf[:i]
+		remaining, err := codec.SkipBinary(short)
+		ensureError(t, err, "cannot skip binary record")
+		if len(remaining) != len(short) {
+			t.Errorf("Actual: %v; Expected: %v", len(remaining), len(short))
+		}
+	}
+
+	testSkipBinaryError(t, `{"type":"enum","name":"e1","symbols":["alpha"]}`, []byte{0x2}, "index ought to be between 0 and 0")
+	testSkipBinaryError(t, `["null","int"]`, []byte{0x4}, "index ought to be between 0 and 1")
+	testSkipBinaryError(t, `"string"`, []byte{0x1}, "negative size")
+	testSkipBinaryError(t, `{"type":"array","items":"int"}`, []byte{0x3, 0x8, 0x2}, "block size")
+	testSkipBinaryError(t, `{"type":"map","values":"int"}`, []byte{0x2, 0x2, 'k'}, "cannot skip binary map value")
+
+	// block counts near 2^62 are rejected rather than skipped one item at a time
+	hugeBlockCount := []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01}
+	testSkipBinaryError(t, `{"type":"array","items":"null"}`, hugeBlockCount, "cannot skip binary array when block count exceeds MaxBlockCount")
+	testSkipBinaryError(t, `{"type":"map","values":"null"}`, hugeBlockCount, "cannot skip binary map when block count exceeds MaxBlockCount")
+}
+
+func TestCodecSkipBinaryErrorPath(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r","fields":[
+		{"name":"m","type":{"type":"map","values":{"type":"array","items":["null","boolean"]}}}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// map of one entry, key "k", whose array holds a null and a truncated boolean
+	_, err = codec.SkipBinary([]byte{0x2, 0x2, 'k', 0x4, 0x0, 0x2})
+	if !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("Actual: %v; Expected: %v", err, io.ErrShortBuffer)
+	}
+	var de *goavro.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("Actual: %T; Expected: %T", err, de)
+	}
+	if actual, expected := de.Path, "/m/k/1/boolean"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+	ensureError(t, err, "cannot skip binary record \"r\" field \"m\": cannot skip binary map value: cannot skip binary array item 2: cannot skip binary union item 2: cannot skip binary boolean")
+}
+
+func testSkipBinaryError(t *testing.T, schema string, buf []byte, contains string) {
+	t.Helper()
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.SkipBinary(buf)
+	ensureError(t, err, contains)
+}
+
+func TestCodecSkipBinaryDoesNotAllocate(t *testing.T) {
+	codec, buf := newSkipTestBinary(t)
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := codec.SkipBinary(buf); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Actual: %v; Expected: %v", allocs, 0)
+	}
+}
+
+func BenchmarkCodecSkipBinary(b *testing.B) {
+	codec, buf := newSkipTestBinary(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := codec.SkipBinary(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCodecNativeFromBinaryInto(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r","fields":[
+		{"name":"items","type":{"type":"array","items":{"type":"record","name":"item","fields":[{"name":"id","type":"long"}]}}},
+		{"name":"tags","type":{"type":"map","values":"string"}},
+		{"name":"u","type":["null","item","string"]}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"id": int64(1)}, map[string]interface{}{"id": int64(2)}},
+		"tags":  map[string]interface{}{"a": "x", "b": "y"},
+		"u":     goavro.Union("item", map[string]interface{}{"id": int64(3)}),
+	}
+	second := map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"id": int64(4)}},
+		"tags":  map[string]interface{}{"c": "z"},
+		"u":     goavro.Union("item", map[string]interface{}{"id": int64(5)}),
+	}
+	third := map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"id": int64(6)}, map[string]interface{}{"id": int64(7)}, map[string]interface{}{"id": int64(8)}},
+		"tags":  map[string]interface{}{},
+		"u":     goavro.Union("string", "s"),
+	}
+
+	var datum interface{}
+	for i, expected := range []map[string]interface{}{first, second, third} {
+		buf, err := codec.BinaryFromNative(nil, expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		previous := datum
+		datum, buf, err = codec.NativeFromBinaryInto(buf, datum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(buf) != 0 {
+			t.Errorf("Actual: %v; Expected: %v", buf, nil)
+		}
+		if !reflect.DeepEqual(datum, expected) {
+			t.Errorf("datum %d; Actual: %#v; Expected: %#v", i, datum, expected)
+		}
+		if previous != nil && reflect.ValueOf(datum).Pointer() != reflect.ValueOf(previous).Pointer() {
+			t.Errorf("datum %d; record map ought to be reused", i)
+		}
+	}
+}
+
+func TestCodecNativeFromBinaryIntoReusesNestedValues(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"array","items":{"type":"record","name":"item","fields":[{"name":"id","type":"long"}]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, []interface{}{map[string]interface{}{"id": int64(1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := datum.([]interface{})[0].(map[string]interface{})
+
+	if buf, err = codec.BinaryFromNative(nil, []interface{}{map[string]interface{}{"id": int64(2)}}); err != nil {
+		t.Fatal(err)
+	}
+	if datum, _, err = codec.NativeFromBinaryInto(buf, datum); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := item["id"], int64(2); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	// NOTE: A datum of a different shape is ignored rather than reused.
+	if datum, _, err = codec.NativeFromBinaryInto(buf, "not an array"); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := datum, []interface{}{map[string]interface{}{"id": int64(2)}}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func BenchmarkCodecNativeFromBinary(b *testing.B) {
+	codec, buf := newSkipTestBinary(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := codec.NativeFromBinary(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCodecNativeFromBinaryInto(b *testing.B) {
+	codec, buf := newSkipTestBinary(b)
+	var datum interface{}
+	var err error
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if datum, _, err = codec.NativeFromBinaryInto(buf, datum); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCodecBinaryFromNative(b *testing.B) {
+	codec, buf := newSkipTestBinary(b)
+	datum, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		b.Fatal(err)
+	}
+	buf = buf[:0]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if buf, err = codec.BinaryFromNative(buf[:0], datum); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkCodecBinaryFromNativeNumeric(b *testing.B, datum map[string]interface{}) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r1","fields":[
+  {"name":"i","type":"int"},
+  {"name":"l","type":"long"},
+  {"name":"f","type":"float"},
+  {"name":"d","type":"double"}
+]}`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var buf []byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if buf, err = codec.BinaryFromNative(buf[:0], datum); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCodecBinaryFromNativeNumericDecodedTypes encodes values of the Go
+// types goavro decodes numeric values as.
+func BenchmarkCodecBinaryFromNativeNumericDecodedTypes(b *testing.B) {
+	benchmarkCodecBinaryFromNativeNumeric(b, map[string]interface{}{"i": int32(-3), "l": int64(1 << 40), "f": float32(3.5), "d": 6.25})
+}
+
+// BenchmarkCodecBinaryFromNativeNumericOtherTypes encodes values of other Go
+// numeric types, which are converted.
+func BenchmarkCodecBinaryFromNativeNumericOtherTypes(b *testing.B) {
+	benchmarkCodecBinaryFromNativeNumeric(b, map[string]interface{}{"i": -3, "l": uint32(1 << 30), "f": 3.5, "d": 6})
+}
@@ -0,0 +1,279 @@
+package goavro
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// CompareBinary compares two data encoded in binary using the Codec's schema,
+// without decoding them, and returns -1, 0, or +1 when a sorts before, the
+// same as, or after b, following the sort order defined by the Avro
+// specification, like BinaryData.compare of the Java implementation:
+//
+//     null              always equal
+//     boolean           false before true
+//     int, long         numerically
+//     float, double     numerically, with -0 before 0, and NaN after all others
+//     bytes, fixed      lexicographically by unsigned byte
+//     string            lexicographically by unsigned byte of its UTF-8 encoding
+//     enum              by the position of the symbol in the schema
+//     array             item by item, then shorter before longer
+//     union             by the position of the member in the union, then by value
+//     record            field by field, honoring the order attribute of each field
+//
+// A record field whose order is "descending" reverses the result of comparing
+// its values, and a field whose order is "ignore" is skipped. Comparing a
+// datum containing a map, outside of an ignored field, returns an error,
+// because the specification does not define an order for maps. Only the
+// leading datum of each buffer is compared.
+//
+//     cmp, err := codec.CompareBinary(a, b)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+func (c *Codec) CompareBinary(a, b []byte) (int, error) {
+	cmp, _, _, err := compareBinary(c.node, a, b)
+	if err != nil {
+		return 0, fmt.Errorf("cannot compare binary %s: %s", c.typeName, err)
+	}
+	return cmp, nil
+}
+
+// CompareNative compares two native data using the same sort order as
+// CompareBinary, and returns an error when either datum cannot be encoded
+// using the Codec's schema.
+func (c *Codec) CompareNative(a, b interface{}) (int, error) {
+	bufA, err := c.binaryFromNative(nil, a)
+	if err != nil {
+		return 0, err
+	}
+	bufB, err := c.binaryFromNative(nil, b)
+	if err != nil {
+		return 0, err
+	}
+	return c.CompareBinary(bufA, bufB)
+}
+
+// compareBinary compares the leading binary datum of a and b, described by
+// schema s. When the data are equal, it also returns the bytes following each
+// datum.
+func compareBinary(s Schema, a, b []byte) (int, []byte, []byte, error) {
+	switch v := s.(type) {
+	case *RecordSchema:
+		for _, field := range v.fields {
+			var cmp int
+			var err error
+			if field.order == OrderIgnore {
+				codec := field.schema.codec()
+				if a, err = codec.skipBinary(a); err != nil {
+					return 0, nil, nil, err
+				}
+				if b, err = codec.skipBinary(b); err != nil {
+					return 0, nil, nil, err
+				}
+				continue
+			}
+			if cmp, a, b, err = compareBinary(field.schema, a, b); err != nil {
+				return 0, nil, nil, fmt.Errorf("record %q field %q: %s", v.Name(), field.name, err)
+			}
+			if cmp != 0 {
+				if field.order == OrderDescending {
+					cmp = -cmp
+				}
+				return cmp, nil, nil, nil
+			}
+		}
+		return 0, a, b, nil
+	case *EnumSchema:
+		return compareLong(a, b)
+	case *FixedSchema:
+		size := int(v.size)
+		if len(a) < size || len(b) < size {
+			return 0, nil, nil, fmt.Errorf("fixed: %w", io.ErrShortBuffer)
+		}
+		return bytes.Compare(a[:size], b[:size]), a[size:], b[size:], nil
+	case *ArraySchema:
+		return compareArray(v, a, b)
+	case *MapSchema:
+		return 0, nil, nil, errors.New("map values have no defined order")
+	case *UnionSchema:
+		indexA, a, err := longFromBinary(a)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		indexB, b, err := longFromBinary(b)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		if indexA != indexB {
+			return compareInt64(indexA, indexB), nil, nil, nil
+		}
+		if indexA < 0 || indexA >= int64(len(v.members)) {
+			return 0, nil, nil, fmt.Errorf("union: index ought to be between 0 and %d; read index: %d", len(v.members)-1, indexA)
+		}
+		return compareBinary(v.members[indexA], a, b)
+	case *PrimitiveSchema:
+		switch v.typeName {
+		case "null":
+			return 0, a, b, nil
+		case "boolean":
+			if len(a) < 1 || len(b) < 1 {
+				return 0, nil, nil, fmt.Errorf("boolean: %w", io.ErrShortBuffer)
+			}
+			return compareInt64(int64(a[0]), int64(b[0])), a[1:], b[1:], nil
+		case "int", "long":
+			return compareLong(a, b)
+		case "float":
+			if len(a) < floatEncodedLength || len(b) < floatEncodedLength {
+				return 0, nil, nil, fmt.Errorf("float: %w", io.ErrShortBuffer)
+			}
+			x := math.Float32frombits(binary.LittleEndian.Uint32(a))
+			y := math.Float32frombits(binary.LittleEndian.Uint32(b))
+			return compareFloat64(float64(x), float64(y)), a[floatEncodedLength:], b[floatEncodedLength:], nil
+		case "double":
+			if len(a) < doubleEncodedLength || len(b) < doubleEncodedLength {
+				return 0, nil, nil, fmt.Errorf("double: %w", io.ErrShortBuffer)
+			}
+			x := math.Float64frombits(binary.LittleEndian.Uint64(a))
+			y := math.Float64frombits(binary.LittleEndian.Uint64(b))
+			return compareFloat64(x, y), a[doubleEncodedLength:], b[doubleEncodedLength:], nil
+		case "bytes", "string":
+			x, a, err := bytesFromBinary(a)
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			y, b, err := bytesFromBinary(b)
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return bytes.Compare(x, y), a, b, nil
+		}
+	}
+	return 0, nil, nil, fmt.Errorf("unsupported schema type: %s", s.Type())
+}
+
+// compareArray compares the items of two binary arrays, which may be encoded
+// using different block sizes, and when all items of the shorter array equal
+// the leading items of the other, sorts the shorter array first.
+func compareArray(s *ArraySchema, a, b []byte) (int, []byte, []byte, error) {
+	var remainingA, remainingB int64 // items remaining in current blocks
+	var err error
+	maxBlockCount := s.c.config.blockCountLimit()
+	for {
+		if remainingA == 0 {
+			if remainingA, a, err = arrayBlockCount(a, maxBlockCount); err != nil {
+				return 0, nil, nil, err
+			}
+		}
+		if remainingB == 0 {
+			if remainingB, b, err = arrayBlockCount(b, maxBlockCount); err != nil {
+				return 0, nil, nil, err
+			}
+		}
+		if remainingA == 0 || remainingB == 0 {
+			// at least one array has no more items
+			return compareInt64(remainingA, remainingB), a, b, nil
+		}
+		if isNullSchema(s.items) {
+			// null items are always equal and encoded as zero bytes
+			n := remainingA
+			if remainingB < n {
+				n = remainingB
+			}
+			remainingA -= n
+			remainingB -= n
+			continue
+		}
+		for remainingA > 0 && remainingB > 0 {
+			var cmp int
+			if cmp, a, b, err = compareBinary(s.items, a, b); err != nil {
+				return 0, nil, nil, fmt.Errorf("array: %s", err)
+			}
+			if cmp != 0 {
+				return cmp, nil, nil, nil
+			}
+			remainingA--
+			remainingB--
+		}
+	}
+}
+
+// arrayBlockCount returns the number of items of the array block at the start
+// of buf, skipping the byte size of the block when the encoder provided it. It
+// returns an error when the block holds more than maxBlockCount items.
+func arrayBlockCount(buf []byte, maxBlockCount int64) (int64, []byte, error) {
+	count, buf, err := longFromBinary(buf)
+	if err != nil {
+		return 0, nil, fmt.Errorf("array: %s", err)
+	}
+	if count < 0 {
+		if count == math.MinInt64 {
+			return 0, nil, fmt.Errorf("array: block count overflow: %d", count)
+		}
+		count = -count
+		if _, buf, err = longFromBinary(buf); err != nil {
+			return 0, nil, fmt.Errorf("array: %s", err)
+		}
+	}
+	// Ensure block count does not exceed some sane value.
+	if count > maxBlockCount {
+		return 0, nil, fmt.Errorf("array: block count exceeds MaxBlockCount: %d > %d", count, maxBlockCount)
+	}
+	return count, buf, nil
+}
+
+// compareLong compares the leading binary int or long values of a and b.
+func compareLong(a, b []byte) (int, []byte, []byte, error) {
+	x, a, err := longFromBinary(a)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	y, b, err := longFromBinary(b)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return compareInt64(x, y), a, b, nil
+}
+
+func compareInt64(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// compareFloat64 compares like Double.compare of Java: -0 sorts before 0, and
+// NaN equals itself and sorts after every other value, including +Inf.
+func compareFloat64(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	nanX, nanY := math.IsNaN(x), math.IsNaN(y)
+	switch {
+	case nanX && nanY:
+		return 0
+	case nanX:
+		return 1
+	case nanY:
+		return -1
+	}
+	// NOTE: Values are equal, but may be zeros of different signs.
+	signX, signY := math.Signbit(x), math.Signbit(y)
+	switch {
+	case signX && !signY:
+		return -1
+	case !signX && signY:
+		return 1
+	}
+	return 0
+}
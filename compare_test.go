@@ -0,0 +1,142 @@
+package goavro_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func testCompare(t *testing.T, schema string, a, b interface{}, expected int) {
+	t.Helper()
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := codec.CompareNative(a, b)
+	if err != nil {
+		t.Fatalf("schema: %s; %s", schema, err)
+	}
+	if actual != expected {
+		t.Errorf("schema: %s; a: %#v; b: %#v; Actual: %d; Expected: %d", schema, a, b, actual, expected)
+	}
+	// comparison is antisymmetric
+	actual, err = codec.CompareNative(b, a)
+	if err != nil {
+		t.Fatalf("schema: %s; %s", schema, err)
+	}
+	if actual != -expected {
+		t.Errorf("schema: %s; a: %#v; b: %#v; Actual: %d; Expected: %d", schema, b, a, actual, -expected)
+	}
+}
+
+func TestCompareBinaryPrimitives(t *testing.T) {
+	testCompare(t, `"null"`, nil, nil, 0)
+	testCompare(t, `"boolean"`, false, true, -1)
+	testCompare(t, `"boolean"`, true, true, 0)
+	testCompare(t, `"int"`, -3, 2, -1)
+	testCompare(t, `"int"`, 64, 63, 1)
+	testCompare(t, `"long"`, int64(math.MinInt64), int64(math.MaxInt64), -1)
+	testCompare(t, `"long"`, 13, 13, 0)
+	testCompare(t, `"float"`, -1.5, 1.5, -1)
+	testCompare(t, `"float"`, math.Copysign(0, -1), 0.0, -1)
+	testCompare(t, `"double"`, math.NaN(), math.Inf(1), 1)
+	testCompare(t, `"double"`, math.NaN(), math.NaN(), 0)
+	testCompare(t, `"double"`, math.Inf(-1), -math.MaxFloat64, -1)
+	testCompare(t, `"string"`, "abc", "abd", -1)
+	testCompare(t, `"string"`, "ab", "abc", -1)
+	testCompare(t, `"string"`, "", "", 0)
+	testCompare(t, `"string"`, "z", "é", -1) // by unsigned UTF-8 byte
+	testCompare(t, `"bytes"`, []byte{0x7f}, []byte{0x80}, -1)
+	testCompare(t, `{"type":"fixed","name":"f1","size":2}`, []byte{1, 0xff}, []byte{2, 0}, -1)
+	testCompare(t, `{"type":"enum","name":"e1","symbols":["zulu","alpha"]}`, "zulu", "alpha", -1)
+	testCompare(t, `{"type":"long","logicalType":"timestamp-millis"}`, 1, 2, -1)
+}
+
+func TestCompareBinaryComplex(t *testing.T) {
+	testCompare(t, `{"type":"array","items":"int"}`, []interface{}{1, 2}, []interface{}{1, 3}, -1)
+	testCompare(t, `{"type":"array","items":"int"}`, []interface{}{1, 2}, []interface{}{1, 2, 0}, -1)
+	testCompare(t, `{"type":"array","items":"int"}`, []interface{}{}, []interface{}{}, 0)
+	testCompare(t, `{"type":"array","items":"int"}`, []interface{}{5}, []interface{}{1, 2, 3}, 1)
+	testCompare(t, `["null","int","string"]`, nil, goavro.Union("int", 3), -1)
+	testCompare(t, `["null","int","string"]`, goavro.Union("string", "a"), goavro.Union("int", 3), 1)
+	testCompare(t, `["null","int","string"]`, goavro.Union("int", 3), goavro.Union("int", 4), -1)
+
+	schema := `{"type":"record","name":"r1","fields":[
+  {"name":"a","type":"int"},
+  {"name":"b","type":"string","order":"descending"},
+  {"name":"c","type":{"type":"map","values":"int"},"order":"ignore"},
+  {"name":"d","type":"long"}
+]}`
+	record := func(a int, b string, c map[string]interface{}, d int64) map[string]interface{} {
+		return map[string]interface{}{"a": a, "b": b, "c": c, "d": d}
+	}
+	testCompare(t, schema, record(1, "x", nil, 0), record(2, "x", nil, 0), -1)
+	testCompare(t, schema, record(1, "x", nil, 0), record(1, "y", nil, 0), 1)
+	testCompare(t, schema, record(1, "x", map[string]interface{}{"k": 1}, 0), record(1, "x", nil, 0), 0)
+	testCompare(t, schema, record(1, "x", nil, 1), record(1, "x", nil, 2), -1)
+}
+
+func TestCompareBinaryBlocks(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"array","items":"int"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// [1, 2] in one block, and in two blocks, the second with its byte size
+	a := []byte{4, 2, 4, 0}
+	b := []byte{2, 2, 1, 1, 4, 0}
+	cmp, err := codec.CompareBinary(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp != 0 {
+		t.Errorf("Actual: %d; Expected: %d", cmp, 0)
+	}
+}
+
+func TestCompareBinaryErrors(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r1","fields":[{"name":"m","type":{"type":"map","values":"int"}}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.CompareBinary([]byte{0}, []byte{0})
+	ensureError(t, err, `cannot compare binary r1: record "r1" field "m": map values have no defined order`)
+
+	codec, err = goavro.NewCodec(`"double"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.CompareBinary([]byte{0}, []byte{0, 0, 0, 0, 0, 0, 0, 0})
+	ensureError(t, err, "cannot compare binary double: double: short buffer")
+
+	_, err = codec.CompareNative("abc", 1.0)
+	ensureError(t, err, "cannot encode binary double")
+}
+
+func TestCompareBinaryBlockCount(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"array","items":"null"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hugeBlockCount := []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x40, 0x0} // 1<<40 items
+	_, err = codec.CompareBinary(hugeBlockCount, hugeBlockCount)
+	ensureError(t, err, "cannot compare binary array: array: block count exceeds MaxBlockCount: 1099511627776 > 2147483647")
+
+	// null items are compared without looping over them
+	a := []byte{0xfe, 0xff, 0xff, 0xff, 0x0f, 0x0} // math.MaxInt32 items
+	b := []byte{0xfc, 0xff, 0xff, 0xff, 0x0f, 0x0} // math.MaxInt32 - 1 items
+	cmp, err := codec.CompareBinary(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp != 1 {
+		t.Errorf("Actual: %d; Expected: %d", cmp, 1)
+	}
+
+	codec, err = goavro.NewCodecWithOptions(`{"type":"array","items":"int"}`, goavro.WithMaxBlockCount(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.CompareBinary([]byte{6, 2, 4, 6, 0}, []byte{0})
+	ensureError(t, err, "block count exceeds MaxBlockCount: 3 > 2")
+}
@@ -7,7 +7,7 @@ import (
 
 // enum does not have child objects, therefore whatever namespace it defines is
 // just to store its name in the symbol table.
-func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
+func makeEnumCodec(st *symbolTable, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
 	c, err := registerNewCodec(st, schemaMap, enclosingNamespace)
 	if err != nil {
 		return nil, fmt.Errorf("Enum ought to have valid name: %s", err)
@@ -33,49 +33,89 @@ func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap ma
 		}
 		symbols[i] = symbol
 	}
+	doc, aliases := attributesFromSchemaMap(schemaMap)
+	enumSchema := &EnumSchema{symbols: symbols}
+	if d, ok := schemaMap["default"]; ok {
+		defaultSymbol, ok := d.(string)
+		if !ok || !containsSymbol(symbols, defaultSymbol) {
+			return nil, fmt.Errorf("Enum %q default ought to be member of symbols: %v; %v", c.typeName, symbols, d)
+		}
+		enumSchema.defaultSymbol = defaultSymbol
+		enumSchema.hasDefault = true
+	}
+	props := propertiesFromSchemaMap(schemaMap, "aliases", "default", "doc", "name", "namespace", "symbols", "type")
+	enumSchema.namedSchema = namedSchema{properties: props, c: c, aliases: aliases, doc: doc}
+	c.node = enumSchema
+	if err = st.runSchemaHooks(c.node.(Annotated)); err != nil {
+		return nil, fmt.Errorf("Enum %q: %s", c.typeName, err)
+	}
 
-	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
-		var value interface{}
-		var err error
-		var index int64
+	// NOTE: Box each symbol in an interface once, so decoding an enum value
+	// does not allocate, and index each symbol, so encoding an enum value does
+	// not search the symbols.
+	valueFromIndex := make([]interface{}, len(symbols))
+	indexFromSymbol := make(map[string]int64, len(symbols))
+	for i, symbol := range symbols {
+		valueFromIndex[i] = symbol
+		indexFromSymbol[symbol] = int64(i)
+	}
 
-		if value, buf, err = longNativeFromBinary(buf); err != nil {
+	// NOTE: When the Codec was created with the WithEnumDefaults option, and
+	// the enum has a default symbol, unknown indexes and symbols decode as the
+	// default symbol, as the Avro specification requires of readers.
+	useDefault := st.config.enumDefaults && enumSchema.hasDefault
+	var defaultValue interface{} = enumSchema.defaultSymbol
+
+	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
+		index, buf, err := longFromBinary(buf)
+		if err != nil {
 			return nil, nil, fmt.Errorf("cannot decode binary enum %q index: %s", c.typeName, err)
 		}
-		index = value.(int64)
 		if index < 0 || index >= int64(len(symbols)) {
+			if useDefault {
+				return defaultValue, buf, nil
+			}
 			return nil, nil, fmt.Errorf("cannot decode binary enum %q: index ought to be between 0 and %d; read index: %d", c.typeName, len(symbols)-1, index)
 		}
-		return symbols[index], buf, nil
+		return valueFromIndex[index], buf, nil
+	}
+	c.skipBinary = func(buf []byte) ([]byte, error) {
+		index, buf, err := longFromBinary(buf)
+		if err != nil {
+			return nil, fmt.Errorf("cannot skip binary enum %q index: %w", c.typeName, err)
+		}
+		if (index < 0 || index >= int64(len(symbols))) && !useDefault {
+			return nil, fmt.Errorf("cannot skip binary enum %q: index ought to be between 0 and %d; read index: %d", c.typeName, len(symbols)-1, index)
+		}
+		return buf, nil
 	}
 	c.binaryFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
 		someString, ok := datum.(string)
 		if !ok {
 			return nil, fmt.Errorf("cannot encode binary enum %q: expected string; received: %T", c.typeName, datum)
 		}
-		for i, symbol := range symbols {
-			if symbol == someString {
-				return longBinaryFromNative(buf, i)
-			}
+		if index, ok := indexFromSymbol[someString]; ok {
+			return appendLong(buf, index), nil
 		}
 		return nil, fmt.Errorf("cannot encode binary enum %q: value ought to be member of symbols: %v; %q", c.typeName, symbols, someString)
 	}
 	c.nativeFromTextual = func(buf []byte) (interface{}, []byte, error) {
 		if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
-			return nil, nil, fmt.Errorf("cannot decode textual enum: %s", io.ErrShortBuffer)
+			return nil, nil, fmt.Errorf("cannot decode textual enum: %w", io.ErrShortBuffer)
 		}
 		// decode enum string
 		var value interface{}
 		var err error
 		value, buf, err = stringNativeFromTextual(buf)
 		if err != nil {
-			return nil, nil, fmt.Errorf("cannot decode textual enum: expected key: %s", err)
+			return nil, nil, fmt.Errorf("cannot decode textual enum: expected key: %w", err)
 		}
 		someString := value.(string)
-		for _, symbol := range symbols {
-			if symbol == someString {
-				return someString, buf, nil
-			}
+		if index, ok := indexFromSymbol[someString]; ok {
+			return valueFromIndex[index], buf, nil
+		}
+		if useDefault {
+			return defaultValue, buf, nil
 		}
 		return nil, nil, fmt.Errorf("cannot decode textual enum %q: value ought to be member of symbols: %v; %q", c.typeName, symbols, someString)
 	}
@@ -94,3 +134,13 @@ func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap ma
 
 	return c, nil
 }
+
+// containsSymbol returns true when symbol is one of symbols.
+func containsSymbol(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,10 @@
 package goavro_test
 
 import (
+	"fmt"
 	"testing"
+
+	"github.com/karrick/goavro"
 )
 
 func TestSchemaEnum(t *testing.T) {
@@ -50,3 +53,58 @@ func TestEnumTextCodec(t *testing.T) {
 	testTextEncodeFail(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"]}`, "charlie", `cannot encode textual enum "e1": value ought to be member of symbols`)
 	testTextDecodeFail(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"]}`, []byte(`"charlie"`), `cannot decode textual enum "e1": value ought to be member of symbols`)
 }
+
+func TestEnumDefault(t *testing.T) {
+	testSchemaValid(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":"bravo"}`)
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":"charlie"}`, `Enum "e1" default ought to be member of symbols: [alpha bravo]; charlie`)
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":3}`, `Enum "e1" default ought to be member of symbols`)
+
+	schema, err := goavro.ParseSchema(`{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":"bravo"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if symbol, ok := schema.(*goavro.EnumSchema).Default(); !ok || symbol != "bravo" {
+		t.Errorf("Actual: %q, %v; Expected: %q, %v", symbol, ok, "bravo", true)
+	}
+	if _, ok := schema.(*goavro.EnumSchema).Properties()["default"]; ok {
+		t.Errorf("Actual: %v; Expected: %v", ok, false)
+	}
+
+	// without the WithEnumDefaults option, unknown indexes and symbols fail
+	testBinaryDecodeFail(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":"bravo"}`, []byte("\x04"), `index ought to be between 0 and 1`)
+	testTextDecodeFail(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":"bravo"}`, []byte(`"charlie"`), `value ought to be member of symbols`)
+}
+
+func TestEnumDefaultsOption(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"record","name":"r1","fields":[
+  {"name":"e1","type":{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":"alpha"}},
+  {"name":"e2","type":{"type":"enum","name":"e2","symbols":["alpha","bravo"]}}
+]}`, goavro.WithEnumDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum, _, err := codec.NativeFromBinary([]byte{4, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", datum), "map[e1:alpha e2:bravo]"; actual != expected {
+		t.Errorf("Actual: %s; Expected: %s", actual, expected)
+	}
+	datum, _, err = codec.NativeFromTextual([]byte(`{"e1":"charlie","e2":"bravo"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", datum), "map[e1:alpha e2:bravo]"; actual != expected {
+		t.Errorf("Actual: %s; Expected: %s", actual, expected)
+	}
+
+	// enums without a default symbol continue to fail
+	_, _, err = codec.NativeFromBinary([]byte{0, 4})
+	ensureError(t, err, `cannot decode binary enum "e2": index ought to be between 0 and 1; read index: 2`)
+	_, _, err = codec.NativeFromTextual([]byte(`{"e1":"alpha","e2":"charlie"}`))
+	ensureError(t, err, `cannot decode textual enum "e2": value ought to be member of symbols`)
+
+	// encoders do not accept unknown symbols
+	_, err = codec.BinaryFromNative(nil, map[string]interface{}{"e1": "charlie", "e2": "alpha"})
+	ensureError(t, err, `cannot encode binary enum "e1": value ought to be member of symbols`)
+}
@@ -0,0 +1,141 @@
+package goavro
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EncodeError is returned when a Codec cannot encode a datum. Its Path locates
+// the datum that could not be encoded within the datum provided to the Codec,
+// using JSON pointer syntax, for instance "/orders/3/lineItems/0/price", where
+// record fields and map values are named by their field name or key, array
+// items by their zero-based index, and non-null union values by the name of
+// their member type, just as they are wrapped by the Union function. The path
+// of the datum provided to the Codec is the empty string.
+//
+// The error may be inspected with errors.Is and errors.As, which examine Err,
+// the underlying reason the datum could not be encoded.
+type EncodeError struct {
+	// Path is the JSON pointer of the datum that could not be encoded.
+	Path string
+
+	// Expected is the Avro type of the datum that could not be encoded, for
+	// instance "long", "array", or the full name of a named type.
+	Expected string
+
+	// Received is the Go type of the datum that could not be encoded.
+	Received string
+
+	// Err is the underlying reason the datum could not be encoded.
+	Err error
+
+	message string
+}
+
+// Error returns the human readable description of the error.
+func (e *EncodeError) Error() string { return e.message }
+
+// Unwrap returns the underlying reason the datum could not be encoded.
+func (e *EncodeError) Unwrap() error { return e.Err }
+
+// DecodeError is returned when a Codec cannot decode a datum. Its Path locates
+// the datum that could not be decoded within the datum being decoded, using
+// the same JSON pointer syntax as EncodeError.
+//
+// The error may be inspected with errors.Is and errors.As, which examine Err,
+// the underlying reason the datum could not be decoded. For instance, when the
+// buffer ends before the datum is complete, errors.Is(err, io.ErrShortBuffer)
+// returns true.
+type DecodeError struct {
+	// Path is the JSON pointer of the datum that could not be decoded.
+	Path string
+
+	// Expected is the Avro type of the datum that could not be decoded, for
+	// instance "long", "array", or the full name of a named type.
+	Expected string
+
+	// Err is the underlying reason the datum could not be decoded.
+	Err error
+
+	message string
+}
+
+// Error returns the human readable description of the error.
+func (e *DecodeError) Error() string { return e.message }
+
+// Unwrap returns the underlying reason the datum could not be decoded.
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// SchemaError is returned when a Codec cannot be created because its schema is
+// not valid.
+type SchemaError struct {
+	// Err is the reason the schema is not valid.
+	Err error
+}
+
+// Error returns the human readable description of the error.
+func (e *SchemaError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the reason the schema is not valid.
+func (e *SchemaError) Unwrap() error { return e.Err }
+
+// asEncodeError returns err when it is already an EncodeError, otherwise it
+// returns an EncodeError describing the failure of codec to encode datum.
+func asEncodeError(err error, codec *Codec, datum interface{}) *EncodeError {
+	if e, ok := err.(*EncodeError); ok {
+		return e
+	}
+	return &EncodeError{
+		Expected: codec.typeName.fullName,
+		Received: fmt.Sprintf("%T", datum),
+		Err:      err,
+		message:  err.Error(),
+	}
+}
+
+// within prepends segment to the path of the error, and returns the error.
+func (e *EncodeError) within(segment string) *EncodeError {
+	e.Path = "/" + escapePathSegment(segment) + e.Path
+	return e
+}
+
+// prefixed prepends the formatted string to the message of the error, and
+// returns the error.
+func (e *EncodeError) prefixed(format string, a ...interface{}) *EncodeError {
+	e.message = fmt.Sprintf(format, a...) + ": " + e.message
+	return e
+}
+
+// asDecodeError returns err when it is already a DecodeError, otherwise it
+// returns a DecodeError describing the failure of codec to decode a datum.
+func asDecodeError(err error, codec *Codec) *DecodeError {
+	if e, ok := err.(*DecodeError); ok {
+		return e
+	}
+	return &DecodeError{
+		Expected: codec.typeName.fullName,
+		Err:      err,
+		message:  err.Error(),
+	}
+}
+
+// within prepends segment to the path of the error, and returns the error.
+func (e *DecodeError) within(segment string) *DecodeError {
+	e.Path = "/" + escapePathSegment(segment) + e.Path
+	return e
+}
+
+// prefixed prepends the formatted string to the message of the error, and
+// returns the error.
+func (e *DecodeError) prefixed(format string, a ...interface{}) *DecodeError {
+	e.message = fmt.Sprintf(format, a...) + ": " + e.message
+	return e
+}
+
+// pathSegmentEscaper escapes the characters of a JSON pointer reference token
+// that have special meaning, as described in RFC 6901.
+var pathSegmentEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePathSegment(segment string) string {
+	return pathSegmentEscaper.Replace(segment)
+}
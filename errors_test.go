@@ -0,0 +1,148 @@
+package goavro_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+const errorsTestSchema = `
+{"type": "record", "name": "Batch",
+ "fields": [
+     {"name": "orders", "type": {"type": "array", "items": {"type": "record", "name": "Order",
+        "fields": [
+            {"name": "lineItems", "type": {"type": "array", "items": {"type": "record", "name": "LineItem",
+               "fields": [{"name": "price", "type": "double"}]}}},
+            {"name": "tags", "type": {"type": "map", "values": ["null", "long"]}}
+        ]}}}
+ ]
+}`
+
+func newErrorsTestDatum(price interface{}, tag interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"orders": []interface{}{
+			map[string]interface{}{
+				"lineItems": []interface{}{map[string]interface{}{"price": 1.5}},
+				"tags":      map[string]interface{}{},
+			},
+			map[string]interface{}{
+				"lineItems": []interface{}{map[string]interface{}{"price": price}},
+				"tags":      map[string]interface{}{"a/b~c": tag},
+			},
+		},
+	}
+}
+
+func TestEncodeErrorPath(t *testing.T) {
+	codec, err := goavro.NewCodec(errorsTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = codec.BinaryFromNative(nil, newErrorsTestDatum("expensive", nil))
+	var encodeError *goavro.EncodeError
+	if !errors.As(err, &encodeError) {
+		t.Fatalf("Actual: %T; Expected: %T", err, encodeError)
+	}
+	if actual, expected := encodeError.Path, "/orders/1/lineItems/0/price"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+	if actual, expected := encodeError.Expected, "double"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+	if actual, expected := encodeError.Received, "string"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+	// message remains readable and unchanged
+	ensureError(t, err, `cannot encode binary record "Batch" field "orders": value does not match its schema: cannot encode binary array item 2`)
+	ensureError(t, err, "cannot encode binary double: expected: Go numeric; received: string")
+
+	_, err = codec.TextualFromNative(nil, newErrorsTestDatum(2.5, goavro.Union("long", "13")))
+	if !errors.As(err, &encodeError) {
+		t.Fatalf("Actual: %T; Expected: %T", err, encodeError)
+	}
+	if actual, expected := encodeError.Path, "/orders/1/tags/a~1b~0c/long"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+	if actual, expected := encodeError.Expected, "long"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+}
+
+func TestEncodeErrorRoot(t *testing.T) {
+	codec, err := goavro.NewCodec(`"long"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.BinaryFromNative(nil, "thirteen")
+	var encodeError *goavro.EncodeError
+	if !errors.As(err, &encodeError) {
+		t.Fatalf("Actual: %T; Expected: %T", err, encodeError)
+	}
+	if encodeError.Path != "" || encodeError.Expected != "long" || encodeError.Received != "string" {
+		t.Errorf("Actual: %#v", encodeError)
+	}
+}
+
+func TestDecodeErrorPath(t *testing.T) {
+	codec, err := goavro.NewCodec(errorsTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, newErrorsTestDatum(2.5, goavro.Union("long", 13)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// truncate within the union value of the last map value
+	_, _, err = codec.NativeFromBinary(buf[:len(buf)-3])
+	var decodeError *goavro.DecodeError
+	if !errors.As(err, &decodeError) {
+		t.Fatalf("Actual: %T; Expected: %T", err, decodeError)
+	}
+	if actual, expected := decodeError.Path, "/orders/1/tags/a~1b~0c/long"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+	if actual, expected := decodeError.Expected, "long"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+	if !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("Actual: %v; Expected: %v", err, io.ErrShortBuffer)
+	}
+
+	// truncate within the price of the second order
+	_, _, err = codec.NativeFromBinary(buf[:len(buf)-14])
+	if !errors.As(err, &decodeError) {
+		t.Fatalf("Actual: %T; Expected: %T", err, decodeError)
+	}
+	if actual, expected := decodeError.Path, "/orders/1/lineItems/0/price"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+	if !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("Actual: %v; Expected: %v", err, io.ErrShortBuffer)
+	}
+
+	_, _, err = codec.NativeFromTextual([]byte(`{"orders":[{"lineItems":[],"tags":{"x":{"long":"13"}}}]}`))
+	if !errors.As(err, &decodeError) {
+		t.Fatalf("Actual: %T; Expected: %T", err, decodeError)
+	}
+	if actual, expected := decodeError.Path, "/orders/0/tags/x/long"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+}
+
+func TestSchemaError(t *testing.T) {
+	_, err := goavro.NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1"}]}`)
+	var schemaError *goavro.SchemaError
+	if !errors.As(err, &schemaError) {
+		t.Fatalf("Actual: %T; Expected: %T", err, schemaError)
+	}
+	ensureError(t, err, "Record \"r1\" field 1 ought to be valid Avro named type")
+
+	_, err = goavro.NewCodec(`{"type":`)
+	if !errors.As(err, &schemaError) {
+		t.Fatalf("Actual: %T; Expected: %T", err, schemaError)
+	}
+}
@@ -2,11 +2,12 @@ package goavro
 
 import (
 	"fmt"
+	"io"
 )
 
 // Fixed does not have child objects, therefore whatever namespace it defines is
 // just to store its name in the symbol table.
-func makeFixedCodec(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
+func makeFixedCodec(st *symbolTable, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
 	c, err := registerNewCodec(st, schemaMap, enclosingNamespace)
 	if err != nil {
 		return nil, fmt.Errorf("Fixed ought to have valid name: %s", err)
@@ -21,14 +22,30 @@ func makeFixedCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 		return nil, fmt.Errorf("Fixed %q size ought to be number greater than zero: %v", c.typeName, s1)
 	}
 	size := uint(s2)
+	doc, aliases := attributesFromSchemaMap(schemaMap)
+	props := propertiesFromSchemaMap(schemaMap, "aliases", "doc", "name", "namespace", "size", "type")
+	c.node = &FixedSchema{namedSchema: namedSchema{properties: props, c: c, aliases: aliases, doc: doc}, size: size}
+	if err = st.runSchemaHooks(c.node.(Annotated)); err != nil {
+		return nil, fmt.Errorf("Fixed %q: %s", c.typeName, err)
+	}
 
 	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
 		if buflen := uint(len(buf)); size > buflen {
-			return nil, nil, fmt.Errorf("cannot decode binary fixed %q: schema size exceeds remaining buffer size: %d > %d (short buffer)", c.typeName, size, buflen)
+			return nil, nil, fmt.Errorf("cannot decode binary fixed %q: schema size exceeds remaining buffer size: %d > %d (%w)", c.typeName, size, buflen, io.ErrShortBuffer)
+		}
+		if st.config.copyBytes {
+			return append([]byte(nil), buf[:size]...), buf[size:], nil
 		}
 		return buf[:size], buf[size:], nil
 	}
 
+	c.skipBinary = func(buf []byte) ([]byte, error) {
+		if buflen := uint(len(buf)); size > buflen {
+			return nil, fmt.Errorf("cannot skip binary fixed %q: schema size exceeds remaining buffer size: %d > %d (%w)", c.typeName, size, buflen, io.ErrShortBuffer)
+		}
+		return buf[size:], nil
+	}
+
 	c.binaryFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
 		someBytes, ok := datum.([]byte)
 		if !ok {
@@ -42,7 +59,7 @@ func makeFixedCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 
 	c.nativeFromTextual = func(buf []byte) (interface{}, []byte, error) {
 		if buflen := uint(len(buf)); size > buflen {
-			return nil, nil, fmt.Errorf("cannot decode textual fixed %q: schema size exceeds remaining buffer size: %d > %d (short buffer)", c.typeName, size, buflen)
+			return nil, nil, fmt.Errorf("cannot decode textual fixed %q: schema size exceeds remaining buffer size: %d > %d (%w)", c.typeName, size, buflen, io.ErrShortBuffer)
 		}
 		var datum interface{}
 		var err error
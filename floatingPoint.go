@@ -20,23 +20,43 @@ const (
 
 func doubleNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 	if len(buf) < doubleEncodedLength {
-		return nil, nil, fmt.Errorf("cannot decode binary double: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode binary double: %w", io.ErrShortBuffer)
 	}
 	return math.Float64frombits(binary.LittleEndian.Uint64(buf[:doubleEncodedLength])), buf[doubleEncodedLength:], nil
 }
 
 func floatNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 	if len(buf) < floatEncodedLength {
-		return nil, nil, fmt.Errorf("cannot decode binary float: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode binary float: %w", io.ErrShortBuffer)
 	}
 	return math.Float32frombits(binary.LittleEndian.Uint32(buf[:floatEncodedLength])), buf[floatEncodedLength:], nil
 }
 
+func doubleSkipBinary(buf []byte) ([]byte, error) {
+	if len(buf) < doubleEncodedLength {
+		return nil, fmt.Errorf("cannot skip binary double: %w", io.ErrShortBuffer)
+	}
+	return buf[doubleEncodedLength:], nil
+}
+
+func floatSkipBinary(buf []byte) ([]byte, error) {
+	if len(buf) < floatEncodedLength {
+		return nil, fmt.Errorf("cannot skip binary float: %w", io.ErrShortBuffer)
+	}
+	return buf[floatEncodedLength:], nil
+}
+
 ////////////////////////////////////////
 // Binary Encode
 ////////////////////////////////////////
 
 func doubleBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
+	return doubleBinaryFromNumeric(buf, datum, datum)
+}
+
+// doubleBinaryFromNumeric encodes datum, which may have been converted from the
+// caller's original datum, named in errors.
+func doubleBinaryFromNumeric(buf []byte, datum, original interface{}) ([]byte, error) {
 	var value float64
 	switch v := datum.(type) {
 	case float64:
@@ -45,23 +65,36 @@ func doubleBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 		value = float64(v)
 	case int:
 		if value = float64(v); int(value) != v {
-			return nil, fmt.Errorf("cannot encode binary double: provided Go int would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary double: provided Go %T would lose precision: %d", original, v)
 		}
 	case int64:
 		if value = float64(v); int64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary double: provided Go int64 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary double: provided Go %T would lose precision: %d", original, v)
 		}
 	case int32:
 		if value = float64(v); int32(value) != v {
-			return nil, fmt.Errorf("cannot encode binary double: provided Go int32 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary double: provided Go %T would lose precision: %d", original, v)
 		}
 	default:
+		converted, err := numericFromNative(datum)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode binary double: %s", err)
+		}
+		if converted != nil {
+			return doubleBinaryFromNumeric(buf, converted, original)
+		}
 		return nil, fmt.Errorf("cannot encode binary double: expected: Go numeric; received: %T", datum)
 	}
 	return floatingBinaryEncoder(buf, uint64(math.Float64bits(value)), doubleEncodedLength)
 }
 
 func floatBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
+	return floatBinaryFromNumeric(buf, datum, datum)
+}
+
+// floatBinaryFromNumeric encodes datum, which may have been converted from the
+// caller's original datum, named in errors.
+func floatBinaryFromNumeric(buf []byte, datum, original interface{}) ([]byte, error) {
 	var value float32
 	switch v := datum.(type) {
 	case float32:
@@ -69,21 +102,28 @@ func floatBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 	case float64:
 		// Assume runtime can cast special floats correctly
 		if value = float32(v); !math.IsNaN(v) && !math.IsInf(v, 1) && !math.IsInf(v, -1) && float64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary float: provided Go float64 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode binary float: provided Go %T would lose precision: %f", original, v)
 		}
 	case int:
 		if value = float32(v); int(value) != v {
-			return nil, fmt.Errorf("cannot encode binary float: provided Go int would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary float: provided Go %T would lose precision: %d", original, v)
 		}
 	case int64:
 		if value = float32(v); int64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary float: provided Go int64 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary float: provided Go %T would lose precision: %d", original, v)
 		}
 	case int32:
 		if value = float32(v); int32(value) != v {
-			return nil, fmt.Errorf("cannot encode binary float: provided Go int32 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary float: provided Go %T would lose precision: %d", original, v)
 		}
 	default:
+		converted, err := numericFromNative(datum)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode binary float: %s", err)
+		}
+		if converted != nil {
+			return floatBinaryFromNumeric(buf, converted, original)
+		}
 		return nil, fmt.Errorf("cannot encode binary float: expected: Go numeric; received: %T", datum)
 	}
 	return floatingBinaryEncoder(buf, uint64(math.Float32bits(value)), floatEncodedLength)
@@ -239,6 +279,12 @@ func doubleTextualFromNative(buf []byte, datum interface{}) ([]byte, error) {
 }
 
 func floatingTextEncoder(buf []byte, datum interface{}, bitSize int) ([]byte, error) {
+	return floatingTextEncoderAs(buf, datum, datum, bitSize)
+}
+
+// floatingTextEncoderAs encodes datum, which may have been converted from the caller's
+// original datum, named in errors.
+func floatingTextEncoderAs(buf []byte, datum, original interface{}, bitSize int) ([]byte, error) {
 	var isFloat bool
 	var someFloat64 float64
 	var someInt64 int64
@@ -252,24 +298,31 @@ func floatingTextEncoder(buf []byte, datum interface{}, bitSize int) ([]byte, er
 	case int:
 		if someInt64 = int64(v); int(someInt64) != v {
 			if bitSize == 64 {
-				return nil, fmt.Errorf("cannot encode textual double: provided Go int would lose precision: %d", v)
+				return nil, fmt.Errorf("cannot encode textual double: provided Go %T would lose precision: %d", original, v)
 			}
-			return nil, fmt.Errorf("cannot encode textual float: provided Go int would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode textual float: provided Go %T would lose precision: %d", original, v)
 		}
 	case int64:
 		someInt64 = v
 	case int32:
 		if someInt64 = int64(v); int32(someInt64) != v {
 			if bitSize == 64 {
-				return nil, fmt.Errorf("cannot encode textual double: provided Go int32 would lose precision: %d", v)
+				return nil, fmt.Errorf("cannot encode textual double: provided Go %T would lose precision: %d", original, v)
 			}
-			return nil, fmt.Errorf("cannot encode textual float: provided Go int32 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode textual float: provided Go %T would lose precision: %d", original, v)
 		}
 	default:
+		converted, err := numericFromNative(datum)
+		if err == nil && converted != nil {
+			return floatingTextEncoderAs(buf, converted, original, bitSize)
+		}
+		if err == nil {
+			err = fmt.Errorf("expected: Go numeric; received: %T", datum)
+		}
 		if bitSize == 64 {
-			return nil, fmt.Errorf("cannot encode textual double: expected: Go numeric; received: %T", datum)
+			return nil, fmt.Errorf("cannot encode textual double: %s", err)
 		}
-		return nil, fmt.Errorf("cannot encode textual float: expected: Go numeric; received: %T", datum)
+		return nil, fmt.Errorf("cannot encode textual float: %s", err)
 	}
 
 	if isFloat {
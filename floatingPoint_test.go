@@ -1,6 +1,7 @@
 package goavro_test
 
 import (
+	"encoding/json"
 	"math"
 	"testing"
 )
@@ -66,3 +67,20 @@ func TestPrimitiveFloatText(t *testing.T) {
 	testTextDecodePass(t, "float", -0, []byte("-0"))
 	testTextEncodePass(t, "float", -0, []byte("0")) // NOTE: -0 encodes as "0"
 }
+
+type testRatio float64
+
+func TestPrimitiveFloatingPointOtherGoTypes(t *testing.T) {
+	testBinaryEncodePass(t, "double", testRatio(3.5), []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0xc, 0x40})
+	testBinaryEncodePass(t, "double", json.Number("3.5"), []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0xc, 0x40})
+	testBinaryEncodePass(t, "double", uint16(3), []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x8, 0x40})
+	testBinaryEncodePass(t, "float", testRatio(3.5), []byte{0x0, 0x0, 0x60, 0x40})
+	testBinaryEncodePass(t, "float", int8(3), []byte{0x0, 0x0, 0x40, 0x40})
+	testBinaryEncodeFail(t, "float", json.Number("3.1"), "cannot encode binary float: provided Go json.Number would lose precision")
+	testBinaryEncodeFail(t, "double", json.Number("x"), "cannot encode binary double: provided Go json.Number is not a number")
+
+	testTextEncodePass(t, "double", json.Number("3.5"), []byte("3.5"))
+	testTextEncodePass(t, "double", uint8(3), []byte("3"))
+	testTextEncodePass(t, "float", testRatio(0.5), []byte("0.5"))
+	testTextEncodeFail(t, "float", "3.5", "cannot encode textual float: expected: Go numeric; received: string")
+}
@@ -32,6 +32,17 @@ func intNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 }
 
 func longNativeFromBinary(buf []byte) (interface{}, []byte, error) {
+	value, buf, err := longFromBinary(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, buf, nil
+}
+
+// longFromBinary decodes a variable-length zig-zag encoded long without boxing
+// it in an interface, so callers that only need the number, such as block
+// counts and sizes, do not allocate.
+func longFromBinary(buf []byte) (int64, []byte, error) {
 	var offset int
 	var value uint64
 	var shift uint
@@ -43,7 +54,26 @@ func longNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 		}
 		shift += 7
 	}
-	return nil, nil, io.ErrShortBuffer
+	return 0, nil, io.ErrShortBuffer
+}
+
+func intSkipBinary(buf []byte) ([]byte, error) {
+	return integerSkipBinary(buf, "int")
+}
+
+func longSkipBinary(buf []byte) ([]byte, error) {
+	return integerSkipBinary(buf, "long")
+}
+
+// integerSkipBinary skips a single variable-length zig-zag encoded integer, and
+// is used to skip both int and long values.
+func integerSkipBinary(buf []byte, kind string) ([]byte, error) {
+	for offset, b := range buf {
+		if b&intFlag == 0 {
+			return buf[offset+1:], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot skip binary %s: %w", kind, io.ErrShortBuffer)
 }
 
 ////////////////////////////////////////
@@ -51,27 +81,40 @@ func longNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 ////////////////////////////////////////
 
 func intBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
+	return intBinaryFromNumeric(buf, datum, datum)
+}
+
+// intBinaryFromNumeric encodes datum, which may have been converted from the
+// caller's original datum, named in errors.
+func intBinaryFromNumeric(buf []byte, datum, original interface{}) ([]byte, error) {
 	var value int32
 	switch v := datum.(type) {
 	case int32:
 		value = v
 	case int:
 		if value = int32(v); int(value) != v {
-			return nil, fmt.Errorf("cannot encode binary int: provided Go int would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary int: provided Go %T would lose precision: %d", original, v)
 		}
 	case int64:
 		if value = int32(v); int64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary int: provided Go int64 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary int: provided Go %T would lose precision: %d", original, v)
 		}
 	case float64:
 		if value = int32(v); float64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary int: provided Go float64 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode binary int: provided Go %T would lose precision: %f", original, v)
 		}
 	case float32:
 		if value = int32(v); float32(value) != v {
-			return nil, fmt.Errorf("cannot encode binary int: provided Go float32 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode binary int: provided Go %T would lose precision: %f", original, v)
 		}
 	default:
+		converted, err := numericFromNative(datum)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode binary int: %s", err)
+		}
+		if converted != nil {
+			return intBinaryFromNumeric(buf, converted, original)
+		}
 		return nil, fmt.Errorf("cannot encode binary int: expected: Go numeric; received: %T", datum)
 	}
 	encoded := uint64((uint32(value) << 1) ^ uint32(value>>intDownShift))
@@ -79,6 +122,12 @@ func intBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 }
 
 func longBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
+	return longBinaryFromNumeric(buf, datum, datum)
+}
+
+// longBinaryFromNumeric encodes datum, which may have been converted from the
+// caller's original datum, named in errors.
+func longBinaryFromNumeric(buf []byte, datum, original interface{}) ([]byte, error) {
 	var value int64
 	switch v := datum.(type) {
 	case int64:
@@ -89,17 +138,32 @@ func longBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 		value = int64(v)
 	case float64:
 		if value = int64(v); float64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary long: provided Go float64 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode binary long: provided Go %T would lose precision: %f", original, v)
 		}
 	case float32:
 		if value = int64(v); float32(value) != v {
-			return nil, fmt.Errorf("cannot encode binary long: provided Go float32 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode binary long: provided Go %T would lose precision: %f", original, v)
 		}
 	default:
+		converted, err := numericFromNative(datum)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode binary long: %s", err)
+		}
+		if converted != nil {
+			return longBinaryFromNumeric(buf, converted, original)
+		}
 		return nil, fmt.Errorf("long: expected: Go numeric; received: %T", datum)
 	}
+	return appendLong(buf, value), nil
+}
+
+// appendLong appends the variable-length zig-zag encoding of value to buf
+// without boxing it in an interface, so callers that encode lengths, block
+// counts, and indexes do not allocate.
+func appendLong(buf []byte, value int64) []byte {
 	encoded := (uint64(value) << 1) ^ uint64(value>>longDownShift)
-	return integerBinaryEncoder(buf, encoded)
+	buf, _ = integerBinaryEncoder(buf, encoded) // never returns an error
+	return buf
 }
 
 func integerBinaryEncoder(buf []byte, encoded uint64) ([]byte, error) {
@@ -158,6 +222,12 @@ func intTextualFromNative(buf []byte, datum interface{}) ([]byte, error) {
 }
 
 func integerTextEncoder(buf []byte, datum interface{}, bitSize int) ([]byte, error) {
+	return integerTextEncoderAs(buf, datum, datum, bitSize)
+}
+
+// integerTextEncoderAs encodes datum, which may have been converted from the caller's
+// original datum, named in errors.
+func integerTextEncoderAs(buf []byte, datum, original interface{}, bitSize int) ([]byte, error) {
 	var someInt64 int64
 	switch v := datum.(type) {
 	case int:
@@ -169,22 +239,32 @@ func integerTextEncoder(buf []byte, datum interface{}, bitSize int) ([]byte, err
 	case float32:
 		if someInt64 = int64(v); float32(someInt64) != v {
 			if bitSize == 64 {
-				return nil, fmt.Errorf("cannot encode textual long: provided Go float32 would lose precision: %f", v)
+				return nil, fmt.Errorf("cannot encode textual long: provided Go %T would lose precision: %f", original, v)
 			}
-			return nil, fmt.Errorf("cannot encode textual int: provided Go float32 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode textual int: provided Go %T would lose precision: %f", original, v)
 		}
 	case float64:
 		if someInt64 = int64(v); float64(someInt64) != v {
 			if bitSize == 64 {
-				return nil, fmt.Errorf("cannot encode textual long: provided Go float64 would lose precision: %f", v)
+				return nil, fmt.Errorf("cannot encode textual long: provided Go %T would lose precision: %f", original, v)
 			}
-			return nil, fmt.Errorf("cannot encode textual int: provided Go float64 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode textual int: provided Go %T would lose precision: %f", original, v)
 		}
 	default:
+		converted, err := numericFromNative(datum)
+		if err == nil && converted != nil {
+			return integerTextEncoderAs(buf, converted, original, bitSize)
+		}
+		if err == nil {
+			err = fmt.Errorf("expected: Go numeric; received: %T", datum)
+		}
 		if bitSize == 64 {
-			return nil, fmt.Errorf("cannot encode textual long: expected: Go numeric; received: %T", datum)
+			return nil, fmt.Errorf("cannot encode textual long: %s", err)
 		}
-		return nil, fmt.Errorf("cannot encode textual int: expected: Go numeric; received: %T", datum)
+		return nil, fmt.Errorf("cannot encode textual int: %s", err)
+	}
+	if bitSize == 32 && int64(int32(someInt64)) != someInt64 {
+		return nil, fmt.Errorf("cannot encode textual int: provided Go %T would lose precision: %d", original, someInt64)
 	}
 	return strconv.AppendInt(buf, someInt64, 10), nil
 }
@@ -1,6 +1,8 @@
 package goavro_test
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
 )
 
@@ -74,3 +76,41 @@ func TestPrimitiveLongText(t *testing.T) {
 	testTextDecodePass(t, "long", -0, []byte("-0"))
 	testTextEncodePass(t, "long", -0, []byte("0")) // NOTE: -0 encodes as "0"
 }
+
+type testUserID int64
+
+type testCount uint16
+
+func TestPrimitiveIntegerOtherGoTypes(t *testing.T) {
+	for _, schema := range []string{"int", "long"} {
+		testBinaryEncodePass(t, schema, int8(-3), []byte{0x05})
+		testBinaryEncodePass(t, schema, int16(-65), []byte("\x81\x01"))
+		testBinaryEncodePass(t, schema, uint8(3), []byte{0x06})
+		testBinaryEncodePass(t, schema, uint16(64), []byte("\x80\x01"))
+		testBinaryEncodePass(t, schema, uint32(66052), []byte("\x88\x88\x08"))
+		testBinaryEncodePass(t, schema, uint(1016), []byte("\xf0\x0f"))
+		testBinaryEncodePass(t, schema, uint64(1), []byte{0x02})
+		testBinaryEncodePass(t, schema, testUserID(-1), []byte{0x01})
+		testBinaryEncodePass(t, schema, testCount(1), []byte{0x02})
+		testBinaryEncodePass(t, schema, json.Number("8454660"), []byte("\x88\x88\x88\x08"))
+		testBinaryEncodePass(t, schema, json.Number("3.0"), []byte("\x06"))
+
+		testTextEncodePass(t, schema, int16(-13), []byte("-13"))
+		testTextEncodePass(t, schema, uint32(13), []byte("13"))
+		testTextEncodePass(t, schema, testUserID(13), []byte("13"))
+		testTextEncodePass(t, schema, json.Number("13"), []byte("13"))
+
+		testBinaryEncodeFail(t, schema, json.Number("3.5"), "would lose precision")
+		testBinaryEncodeFail(t, schema, json.Number("three"), "json.Number is not a number")
+		testTextEncodeFail(t, schema, json.Number("three"), "json.Number is not a number")
+		testBinaryEncodeFail(t, schema, uint64(math.MaxUint64), "provided Go uint64 would overflow: 18446744073709551615")
+		testTextEncodeFail(t, schema, uint64(math.MaxUint64), "provided Go uint64 would overflow")
+	}
+
+	testBinaryEncodeFail(t, "int", uint32(math.MaxUint32), "cannot encode binary int: provided Go uint32 would lose precision")
+	testTextEncodeFail(t, "int", uint32(math.MaxUint32), "cannot encode textual int: provided Go uint32 would lose precision")
+	testBinaryEncodeFail(t, "int", uint32(math.MaxInt32+1), "cannot encode binary int: provided Go uint32 would lose precision: 2147483648")
+	testBinaryEncodeFail(t, "float", uint64(1<<53+1), "cannot encode binary float: provided Go uint64 would lose precision")
+	testBinaryEncodePass(t, "long", uint32(math.MaxUint32), []byte{0xfe, 0xff, 0xff, 0xff, 0x1f})
+	testBinaryEncodePass(t, "long", uint64(math.MaxInt64), []byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x1})
+}
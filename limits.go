@@ -0,0 +1,266 @@
+package goavro
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// The errors wrapped by the DecodeError returned when decoding binary data
+// would exceed one of the limits set by the WithMaxDecodedBytes,
+// WithMaxDepth, WithMaxStringLength, and WithMaxItems options. They may be
+// detected using errors.Is.
+var (
+	ErrMaxDecodedBytes = errors.New("decoded datum exceeds maximum number of bytes")
+	ErrMaxDepth        = errors.New("decoded datum exceeds maximum nesting depth")
+	ErrMaxStringLength = errors.New("decoded string exceeds maximum length")
+	ErrMaxItems        = errors.New("decoded datum exceeds maximum number of items")
+)
+
+// valueCost is the number of bytes counted toward the WithMaxDecodedBytes limit
+// for each decoded value, in addition to the length of strings, bytes, and
+// fixed values. It approximates the size of the interface holding the value.
+const valueCost = 16
+
+// WithMaxDecodedBytes returns an option that limits the approximate number of
+// bytes allocated while decoding a single binary datum, counting the length of
+// each string, bytes, and fixed value, and 16 bytes for each value.
+func WithMaxDecodedBytes(size int64) CodecOption {
+	return func(config *codecConfig) {
+		config.maxDecodedBytes = size
+	}
+}
+
+// WithMaxDepth returns an option that limits the nesting depth of records,
+// arrays, and maps within a single binary datum, which bounds the length of
+// recursive data, such as a linked list. The datum itself is at depth 1.
+func WithMaxDepth(depth int) CodecOption {
+	return func(config *codecConfig) {
+		config.maxDepth = depth
+	}
+}
+
+// WithMaxStringLength returns an option that limits the length of each string,
+// bytes value, and map key within a binary datum.
+func WithMaxStringLength(length int64) CodecOption {
+	return func(config *codecConfig) {
+		config.maxStringLength = length
+	}
+}
+
+// WithMaxItems returns an option that limits the total number of array items
+// and map entries within a single binary datum.
+func WithMaxItems(count int64) CodecOption {
+	return func(config *codecConfig) {
+		config.maxItems = count
+	}
+}
+
+// hasDecodingLimits returns true when any limit on the size of decoded data
+// is set.
+func (config *codecConfig) hasDecodingLimits() bool {
+	return config.maxDecodedBytes > 0 || config.maxDepth > 0 || config.maxStringLength > 0 || config.maxItems > 0
+}
+
+// decodeBudget tracks the resources a binary datum will consume when decoded,
+// and returns an error as soon as a limit would be exceeded.
+type decodeBudget struct {
+	config *codecConfig
+	bytes  int64
+	items  int64
+}
+
+// checkDecodingLimits returns an error when decoding the binary datum at the
+// start of buf, described by s, would exceed a limit of config. It walks the
+// binary data without decoding it.
+//
+// NOTE: Each value is counted where it is held: the datum itself here, fields
+// by their record, and items by the block of their array or map, before any of
+// them is read, and before the decoder preallocates room for them. The scan of
+// a value only counts its contents.
+func checkDecodingLimits(config *codecConfig, s Schema, buf []byte) error {
+	b := &decodeBudget{config: config}
+	if err := b.addBytes(valueCost); err != nil {
+		return err
+	}
+	_, err := b.scan(s, buf, 1)
+	return err
+}
+
+func (b *decodeBudget) addBytes(size int64) error {
+	b.bytes += size
+	if max := b.config.maxDecodedBytes; max > 0 && b.bytes > max {
+		return fmt.Errorf("%w: %d > %d", ErrMaxDecodedBytes, b.bytes, max)
+	}
+	return nil
+}
+
+func (b *decodeBudget) addItems(kind string, count int64) error {
+	b.items += count
+	if max := b.config.maxItems; max > 0 && b.items > max {
+		return fmt.Errorf("cannot decode binary %s: %w: %d > %d", kind, ErrMaxItems, b.items, max)
+	}
+	return nil
+}
+
+func (b *decodeBudget) checkDepth(kind string, depth int) error {
+	if max := b.config.maxDepth; max > 0 && depth > max {
+		return fmt.Errorf("cannot decode binary %s: %w: %d > %d", kind, ErrMaxDepth, depth, max)
+	}
+	return nil
+}
+
+// scanString consumes a string or bytes value, described by kind, from buf,
+// ensuring its length does not exceed the limits.
+func (b *decodeBudget) scanString(kind string, buf []byte) ([]byte, error) {
+	size, buf, err := longFromBinary(buf)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode binary %s: %w", kind, err)
+	}
+	if size < 0 || size > int64(len(buf)) {
+		return nil, fmt.Errorf("cannot decode binary %s: size: %d: %w", kind, size, io.ErrShortBuffer)
+	}
+	if max := b.config.maxStringLength; max > 0 && size > max {
+		return nil, fmt.Errorf("cannot decode binary %s: %w: %d > %d", kind, ErrMaxStringLength, size, max)
+	}
+	if err = b.addBytes(size); err != nil {
+		return nil, err
+	}
+	return buf[size:], nil
+}
+
+// scanBlockCount consumes the block count, and the block size when present,
+// of the next block of an array or map, described by kind, from buf, ensuring
+// neither exceeds the limits checked by the decoders, and counts the items of
+// the block.
+func (b *decodeBudget) scanBlockCount(kind string, buf []byte) (int64, []byte, error) {
+	blockCount, buf, err := longFromBinary(buf)
+	if err != nil {
+		return 0, nil, fmt.Errorf("cannot decode binary %s block count: %w", kind, err)
+	}
+	if blockCount < 0 {
+		if blockCount == math.MinInt64 {
+			return 0, nil, fmt.Errorf("cannot decode binary %s with block count: %d", kind, blockCount)
+		}
+		blockCount = -blockCount
+		var blockSize int64
+		if blockSize, buf, err = longFromBinary(buf); err != nil {
+			return 0, nil, fmt.Errorf("cannot decode binary %s block size: %w", kind, err)
+		}
+		if maxBlockSize := b.config.blockSizeLimit(); blockSize > maxBlockSize {
+			return 0, nil, fmt.Errorf("cannot decode binary %s when block size exceeds MaxBlockSize: %d > %d", kind, blockSize, maxBlockSize)
+		}
+	}
+	if err = b.addItems(kind, blockCount); err != nil {
+		return 0, nil, err
+	}
+	if maxBlockCount := b.config.blockCountLimit(); blockCount > maxBlockCount {
+		return 0, nil, fmt.Errorf("cannot decode binary %s when block count exceeds MaxBlockCount: %d > %d", kind, blockCount, maxBlockCount)
+	}
+	if err = b.addBytes(valueCost * blockCount); err != nil {
+		return 0, nil, err
+	}
+	return blockCount, buf, nil
+}
+
+// scan consumes the binary datum described by s, at the specified nesting
+// depth, from buf, ensuring the limits are not exceeded.
+func (b *decodeBudget) scan(s Schema, buf []byte, depth int) ([]byte, error) {
+	var err error
+	switch v := s.(type) {
+	case *RecordSchema:
+		if err = b.checkDepth("record", depth); err != nil {
+			return nil, err
+		}
+		if err = b.addBytes(valueCost * int64(len(v.fields))); err != nil {
+			return nil, err
+		}
+		for _, field := range v.fields {
+			if buf, err = b.scan(field.schema, buf, depth+1); err != nil {
+				return nil, asDecodeError(err, field.schema.codec()).within(field.name)
+			}
+		}
+		return buf, nil
+	case *ArraySchema:
+		if err = b.checkDepth("array", depth); err != nil {
+			return nil, err
+		}
+		var blockCount, index int64
+		for {
+			if blockCount, buf, err = b.scanBlockCount("array", buf); err != nil {
+				return nil, err
+			}
+			if blockCount == 0 {
+				return buf, nil
+			}
+			if isNullSchema(v.items) {
+				index += blockCount // null items have no contents to scan
+				continue
+			}
+			for i := int64(0); i < blockCount; i++ {
+				if buf, err = b.scan(v.items, buf, depth+1); err != nil {
+					return nil, asDecodeError(err, v.items.codec()).within(strconv.FormatInt(index, 10))
+				}
+				index++
+			}
+		}
+	case *MapSchema:
+		if err = b.checkDepth("map", depth); err != nil {
+			return nil, err
+		}
+		var blockCount int64
+		for {
+			if blockCount, buf, err = b.scanBlockCount("map", buf); err != nil {
+				return nil, err
+			}
+			if blockCount == 0 {
+				return buf, nil
+			}
+			if err = b.addBytes(valueCost * blockCount); err != nil { // keys
+				return nil, err
+			}
+			for i := int64(0); i < blockCount; i++ {
+				start := buf
+				if buf, err = b.scanString("map key", buf); err != nil {
+					return nil, err
+				}
+				key, _, _ := stringFromBinary(start) // already known to be valid
+				if buf, err = b.scan(v.values, buf, depth+1); err != nil {
+					return nil, asDecodeError(err, v.values.codec()).within(key)
+				}
+			}
+		}
+	case *UnionSchema:
+		var index int64
+		if index, buf, err = longFromBinary(buf); err != nil {
+			return nil, err
+		}
+		if index < 0 || index >= int64(len(v.members)) {
+			return nil, fmt.Errorf("index ought to be between 0 and %d; read index: %d", len(v.members)-1, index)
+		}
+		member := v.members[index]
+		if buf, err = b.scan(member, buf, depth); err != nil {
+			return nil, asDecodeError(err, member.codec()).within(member.codec().typeName.fullName)
+		}
+		return buf, nil
+	case *PrimitiveSchema:
+		switch v.typeName {
+		case "string", "bytes":
+			return b.scanString(v.typeName, buf)
+		}
+	case *FixedSchema:
+		if err = b.addBytes(int64(v.size)); err != nil {
+			return nil, err
+		}
+	}
+	return s.codec().skipBinary(buf)
+}
+
+// isNullSchema returns true when s describes the null type, whose values have
+// no binary encoding.
+func isNullSchema(s Schema) bool {
+	p, ok := s.(*PrimitiveSchema)
+	return ok && p.typeName == "null"
+}
@@ -0,0 +1,155 @@
+package goavro_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+const limitsLongListSchema = `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`
+
+func TestWithMaxDepth(t *testing.T) {
+	buf := []byte{0x2, 0x2, 0x2, 0x0} // four nested records
+
+	codec, err := goavro.NewCodecWithOptions(limitsLongListSchema, goavro.WithMaxDepth(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = codec.NativeFromBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	codec, err = goavro.NewCodecWithOptions(limitsLongListSchema, goavro.WithMaxDepth(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, newBuf, err := codec.NativeFromBinary(buf)
+	ensureError(t, err, "exceeds maximum nesting depth: 4 > 3")
+	if !errors.Is(err, goavro.ErrMaxDepth) {
+		t.Errorf("Actual: %v; Expected: %v", err, goavro.ErrMaxDepth)
+	}
+	var de *goavro.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("Actual: %T; Expected: %T", err, de)
+	}
+	if actual, expected := de.Path, "/next/LongList/next/LongList/next/LongList"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+	if !bytes.Equal(newBuf, buf) {
+		t.Errorf("Actual: %v; Expected: %v", newBuf, buf)
+	}
+}
+
+func TestWithMaxStringLength(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"map","values":"bytes"}`, goavro.WithMaxStringLength(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = codec.NativeFromBinary([]byte{0x2, 0x6, 'k', 'e', 'y', 0x6, 'a', 'b', 'c', 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = codec.NativeFromBinary([]byte{0x2, 0x8, 'k', 'e', 'y', 's', 0x2, 'a', 0})
+	ensureError(t, err, "cannot decode binary map key: decoded string exceeds maximum length: 4 > 3")
+	if !errors.Is(err, goavro.ErrMaxStringLength) {
+		t.Errorf("Actual: %v; Expected: %v", err, goavro.ErrMaxStringLength)
+	}
+
+	_, _, err = codec.NativeFromBinary([]byte{0x2, 0x2, 'k', 0x8, 'a', 'b', 'c', 'd', 0})
+	ensureError(t, err, "cannot decode binary bytes: decoded string exceeds maximum length: 4 > 3")
+	var de *goavro.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("Actual: %T; Expected: %T", err, de)
+	}
+	if actual, expected := de.Path, "/k"; actual != expected {
+		t.Errorf("Actual: %q; Expected: %q", actual, expected)
+	}
+}
+
+func TestWithMaxItems(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"array","items":{"type":"array","items":"int"}}`, goavro.WithMaxItems(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// two arrays of one item each, within one array: four items in total
+	if _, _, err = codec.NativeFromBinary([]byte{0x4, 0x2, 0x2, 0x0, 0x2, 0x4, 0x0, 0x0}); err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE: The block count is checked before any item is read, so a huge
+	// block count is rejected without reading its items.
+	_, _, err = codec.NativeFromBinary([]byte{0x4, 0x2, 0x2, 0x0, 0x80, 0x80, 0x80, 0x80, 0x10})
+	ensureError(t, err, "exceeds maximum number of items")
+	if !errors.Is(err, goavro.ErrMaxItems) {
+		t.Errorf("Actual: %v; Expected: %v", err, goavro.ErrMaxItems)
+	}
+}
+
+func TestWithMaxDecodedBytes(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"array","items":"string"}`, goavro.WithMaxDecodedBytes(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the array and two strings of four bytes count for 16+20+20 bytes
+	if _, _, err = codec.NativeFromBinary([]byte{0x4, 0x8, 'a', 'b', 'c', 'd', 0x8, 'e', 'f', 'g', 'h', 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = codec.NativeFromBinary([]byte{0x6, 0x8, 'a', 'b', 'c', 'd', 0x8, 'e', 'f', 'g', 'h', 0x2, 'i', 0})
+	ensureError(t, err, "exceeds maximum number of bytes: 68 > 64")
+	if !errors.Is(err, goavro.ErrMaxDecodedBytes) {
+		t.Errorf("Actual: %v; Expected: %v", err, goavro.ErrMaxDecodedBytes)
+	}
+	if errors.Is(err, goavro.ErrMaxItems) || errors.Is(err, goavro.ErrMaxStringLength) {
+		t.Errorf("Actual: %v; Expected only: %v", err, goavro.ErrMaxDecodedBytes)
+	}
+}
+
+func TestDecodingLimitsHugeBlockOfNulls(t *testing.T) {
+	hugeBlockCount := []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x40, 0x0} // 1<<40 items
+
+	codec, err := goavro.NewCodecWithOptions(`{"type":"array","items":"null"}`, goavro.WithMaxDepth(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = codec.NativeFromBinary(hugeBlockCount)
+	ensureError(t, err, "cannot decode binary array when block count exceeds MaxBlockCount")
+
+	// null items count toward the limits like any other item
+	codec, err = goavro.NewCodecWithOptions(`{"type":"array","items":"null"}`, goavro.WithMaxItems(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = codec.NativeFromBinary([]byte{0xd0, 0xf, 0x0}) // 1000 items
+	if !errors.Is(err, goavro.ErrMaxItems) {
+		t.Errorf("Actual: %v; Expected: %v", err, goavro.ErrMaxItems)
+	}
+
+	codec, err = goavro.NewCodecWithOptions(`{"type":"map","values":"null"}`, goavro.WithMaxDecodedBytes(1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = codec.NativeFromBinary([]byte{0xd0, 0xf, 0x2, 'k', 0x0}) // 1000 entries
+	if !errors.Is(err, goavro.ErrMaxDecodedBytes) {
+		t.Errorf("Actual: %v; Expected: %v", err, goavro.ErrMaxDecodedBytes)
+	}
+}
+
+func TestDecodingLimitsOnlyAffectTheirCodec(t *testing.T) {
+	limited, err := goavro.NewCodecWithOptions(`"string"`, goavro.WithMaxStringLength(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlimited, err := goavro.NewCodec(`"string"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := []byte{0x4, 'a', 'b'}
+	_, _, err = limited.NativeFromBinary(buf)
+	ensureError(t, err, "exceeds maximum length")
+	if _, _, err = unlimited.NativeFromBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+}
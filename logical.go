@@ -0,0 +1,128 @@
+package goavro
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// makeLogicalTypeCodec returns a codec that translates values of the specified
+// logical type using base, the codec of its underlying primitive type, or nil
+// when the logical type is not supported for that primitive type.
+func makeLogicalTypeCodec(base *Codec, logicalType string) *Codec {
+	var toNative func(interface{}) interface{}
+	var fromNative func(interface{}) (interface{}, error)
+
+	switch base.typeName.fullName + "." + logicalType {
+	case "int.date":
+		toNative = func(value interface{}) interface{} {
+			return time.Unix(int64(value.(int32))*secondsPerDay, 0).UTC()
+		}
+		fromNative = func(datum interface{}) (interface{}, error) {
+			t, ok := datum.(time.Time)
+			if !ok {
+				return datum, nil
+			}
+			days := t.Unix() / secondsPerDay
+			if t.Unix()%secondsPerDay < 0 {
+				days-- // round toward negative infinity for dates before the epoch
+			}
+			if days < math.MinInt32 || days > math.MaxInt32 {
+				return nil, fmt.Errorf("cannot encode date: provided Go time.Time would overflow int: %s", t)
+			}
+			return int32(days), nil
+		}
+	case "int.time-millis":
+		toNative = func(value interface{}) interface{} {
+			return time.Duration(value.(int32)) * time.Millisecond
+		}
+		fromNative = func(datum interface{}) (interface{}, error) {
+			d, ok := datum.(time.Duration)
+			if !ok {
+				return datum, nil
+			}
+			if d < 0 || d >= 24*time.Hour {
+				return nil, fmt.Errorf("cannot encode time-millis: provided Go time.Duration ought to be at least 0 and less than 24h: %s", d)
+			}
+			return int32(d / time.Millisecond), nil
+		}
+	case "long.time-micros":
+		toNative = func(value interface{}) interface{} {
+			return time.Duration(value.(int64)) * time.Microsecond
+		}
+		fromNative = func(datum interface{}) (interface{}, error) {
+			d, ok := datum.(time.Duration)
+			if !ok {
+				return datum, nil
+			}
+			if d < 0 || d >= 24*time.Hour {
+				return nil, fmt.Errorf("cannot encode time-micros: provided Go time.Duration ought to be at least 0 and less than 24h: %s", d)
+			}
+			return int64(d / time.Microsecond), nil
+		}
+	case "long.timestamp-millis":
+		toNative = func(value interface{}) interface{} {
+			ms := value.(int64)
+			return time.Unix(ms/1e3, (ms%1e3)*1e6).UTC()
+		}
+		fromNative = func(datum interface{}) (interface{}, error) {
+			t, ok := datum.(time.Time)
+			if !ok {
+				return datum, nil
+			}
+			return t.Unix()*1e3 + int64(t.Nanosecond())/1e6, nil
+		}
+	case "long.timestamp-micros":
+		toNative = func(value interface{}) interface{} {
+			us := value.(int64)
+			return time.Unix(us/1e6, (us%1e6)*1e3).UTC()
+		}
+		fromNative = func(datum interface{}) (interface{}, error) {
+			t, ok := datum.(time.Time)
+			if !ok {
+				return datum, nil
+			}
+			return t.Unix()*1e6 + int64(t.Nanosecond())/1e3, nil
+		}
+	default:
+		return nil
+	}
+
+	c := &Codec{
+		typeName:   base.typeName,
+		config:     base.config,
+		skipBinary: base.skipBinary,
+		nativeFromBinary: func(buf []byte) (interface{}, []byte, error) {
+			value, buf, err := base.nativeFromBinary(buf)
+			if err != nil {
+				return nil, nil, fmt.Errorf("cannot decode binary %s: %w", logicalType, err)
+			}
+			return toNative(value), buf, nil
+		},
+		binaryFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
+			value, err := fromNative(datum)
+			if err != nil {
+				return nil, err
+			}
+			return base.binaryFromNative(buf, value)
+		},
+		nativeFromTextual: func(buf []byte) (interface{}, []byte, error) {
+			value, buf, err := base.nativeFromTextual(buf)
+			if err != nil {
+				return nil, nil, fmt.Errorf("cannot decode textual %s: %w", logicalType, err)
+			}
+			return toNative(value), buf, nil
+		},
+		textualFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
+			value, err := fromNative(datum)
+			if err != nil {
+				return nil, err
+			}
+			return base.textualFromNative(buf, value)
+		},
+	}
+	c.node = &PrimitiveSchema{c: c, typeName: base.typeName.fullName, logicalType: logicalType}
+	return c
+}
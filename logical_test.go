@@ -0,0 +1,148 @@
+package goavro_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karrick/goavro"
+)
+
+func TestLogicalTypesRoundTrip(t *testing.T) {
+	schema := `{"type":"record","name":"Event","fields":[
+		{"name":"day","type":{"type":"int","logicalType":"date"}},
+		{"name":"clock","type":{"type":"int","logicalType":"time-millis"}},
+		{"name":"fine","type":{"type":"long","logicalType":"time-micros"}},
+		{"name":"at","type":{"type":"long","logicalType":"timestamp-millis"}},
+		{"name":"atMicros","type":["null",{"type":"long","logicalType":"timestamp-micros"}]},
+		{"name":"other","type":{"type":"string","logicalType":"uuid"}}
+	]}`
+	codec, err := goavro.NewCodecWithOptions(schema, goavro.WithLogicalTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := map[string]interface{}{
+		"day":      time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC),
+		"clock":    13*time.Hour + 5*time.Millisecond,
+		"fine":     time.Hour + 7*time.Microsecond,
+		"at":       time.Date(1969, 12, 31, 23, 59, 59, 999000000, time.UTC),
+		"atMicros": goavro.Union("long", time.Date(2017, 4, 1, 12, 30, 0, 123456000, time.UTC)),
+		"other":    "2c6b6bbd-3c11-4fa6-8f0e-0c5f4e3a7c11",
+	}
+
+	buf, err := codec.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := decoded, interface{}(datum); !equalLogicalDatum(actual, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	text, err := codec.TextualFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _, err = codec.NativeFromTextual(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := decoded, interface{}(datum); !equalLogicalDatum(actual, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	// the encoded values are the numbers required by the specification
+	plain, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _, err = plain.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := decoded.(map[string]interface{})
+	if actual, expected := values["day"], int32(-1); actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := values["at"], int64(-1); actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := values["clock"], int32(46800005); actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
+
+func TestLogicalTypesAcceptNumbers(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"long","logicalType":"timestamp-millis"}`, goavro.WithLogicalTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := decoded, time.Unix(1, 500000000).UTC(); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := codec.SchemaTree().(*goavro.PrimitiveSchema).LogicalType(), "timestamp-millis"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	_, err = codec.BinaryFromNative(nil, "yesterday")
+	ensureError(t, err, "expected: Go numeric; received: string")
+}
+
+func TestLogicalTypesDateOverflow(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"int","logicalType":"date"}`, goavro.WithLogicalTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.BinaryFromNative(nil, time.Unix(1<<50, 0))
+	ensureError(t, err, "would overflow int")
+}
+
+func TestLogicalTypesTimeOfDayRange(t *testing.T) {
+	for _, schema := range []string{`{"type":"int","logicalType":"time-millis"}`, `{"type":"long","logicalType":"time-micros"}`} {
+		codec, err := goavro.NewCodecWithOptions(schema, goavro.WithLogicalTypes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = codec.BinaryFromNative(nil, 24*time.Hour-time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+		_, err = codec.BinaryFromNative(nil, 24*time.Hour)
+		ensureError(t, err, "ought to be at least 0 and less than 24h")
+		_, err = codec.BinaryFromNative(nil, 30*24*time.Hour) // would overflow int
+		ensureError(t, err, "ought to be at least 0 and less than 24h")
+		_, err = codec.TextualFromNative(nil, -time.Millisecond)
+		ensureError(t, err, "ought to be at least 0 and less than 24h")
+	}
+}
+
+// equalLogicalDatum compares records whose values may be time.Time values,
+// which ought to be compared using their Equal method.
+func equalLogicalDatum(actual, expected interface{}) bool {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok || len(a) != len(e) {
+			return false
+		}
+		for key, value := range e {
+			if !equalLogicalDatum(a[key], value) {
+				return false
+			}
+		}
+		return true
+	case time.Time:
+		a, ok := actual.(time.Time)
+		return ok && a.Equal(e)
+	}
+	return actual == expected
+}
@@ -6,9 +6,10 @@ import (
 	"io"
 	"math"
 	"reflect"
+	"sort"
 )
 
-func makeMapCodec(st map[string]*Codec, namespace string, schemaMap map[string]interface{}) (*Codec, error) {
+func makeMapCodec(st *symbolTable, namespace string, schemaMap map[string]interface{}) (*Codec, error) {
 	// map type must have values
 	valueSchema, ok := schemaMap["values"]
 	if !ok {
@@ -18,126 +19,223 @@ func makeMapCodec(st map[string]*Codec, namespace string, schemaMap map[string]i
 	if err != nil {
 		return nil, fmt.Errorf("Map values ought to be valid Avro type: %s", err)
 	}
+	return newMapCodec(valueCodec, st.config), nil
+}
 
-	return &Codec{
+// newMapCodec returns a codec for maps whose values are translated by
+// valueCodec.
+func newMapCodec(valueCodec *Codec, config *codecConfig) *Codec {
+	c := &Codec{
 		typeName: &name{"map", nullNamespace},
-		nativeFromBinary: func(buf []byte) (interface{}, []byte, error) {
+		config:   config,
+		binaryInto: func(buf []byte, dst interface{}) (interface{}, []byte, error) {
+			maxBlockCount, maxBlockSize := config.blockCountLimit(), config.blockSizeLimit()
+			keyFromBinary := config.stringFromBinary()
+			var blockCount, blockSize int64
 			var err error
 			var value interface{}
 
 			// block count and block size
-			if value, buf, err = longNativeFromBinary(buf); err != nil {
-				return nil, nil, fmt.Errorf("cannot decode binary map block count: %s", err)
+			if blockCount, buf, err = longFromBinary(buf); err != nil {
+				return nil, nil, fmt.Errorf("cannot decode binary map block count: %w", err)
 			}
-			blockCount := value.(int64)
 			if blockCount < 0 {
 				// NOTE: A negative block count implies there is a long encoded
-				// block size following the negative block count. We have no use
-				// for the block size in this decoder, so we read and discard
-				// the value.
+				// block size following the negative block count. This decoder
+				// only uses the block size to reject blocks larger than
+				// MaxBlockSize.
 				if blockCount == math.MinInt64 {
 					// The minimum number for any signed numerical type can
 					// never be made positive
 					return nil, nil, fmt.Errorf("cannot decode binary map with block count: %d", math.MinInt64)
 				}
 				blockCount = -blockCount // convert to its positive equivalent
-				if _, buf, err = longNativeFromBinary(buf); err != nil {
-					return nil, nil, fmt.Errorf("cannot decode binary map block size: %s", err)
+				if blockSize, buf, err = longFromBinary(buf); err != nil {
+					return nil, nil, fmt.Errorf("cannot decode binary map block size: %w", err)
+				}
+				if blockSize > maxBlockSize {
+					return nil, nil, fmt.Errorf("cannot decode binary map when block size exceeds MaxBlockSize: %d > %d", blockSize, maxBlockSize)
 				}
 			}
 			// Ensure block count does not exceed some sane value.
-			if blockCount > MaxBlockCount {
-				return nil, nil, fmt.Errorf("cannot decode binary map when block count exceeds MaxBlockCount: %d > %d", blockCount, MaxBlockCount)
+			if blockCount > maxBlockCount {
+				return nil, nil, fmt.Errorf("cannot decode binary map when block count exceeds MaxBlockCount: %d > %d", blockCount, maxBlockCount)
 			}
 			// NOTE: While the attempt of a RAM optimization shown below is not
 			// necessary, many encoders will encode all items in a single block.
 			// We can optimize amount of RAM allocated by runtime for the array
 			// by initializing the array for that number of items.
-			mapValues := make(map[string]interface{}, blockCount)
+			mapValues, ok := dst.(map[string]interface{})
+			if ok {
+				// NOTE: Values of the previous map are not reused, because
+				// doing so would require tracking which keys were decoded in
+				// order to detect duplicate keys.
+				for key := range mapValues {
+					delete(mapValues, key)
+				}
+			} else {
+				mapValues = make(map[string]interface{}, blockCount)
+			}
 
 			for blockCount != 0 {
 				// Decode `blockCount` datum values from buffer
 				for i := int64(0); i < blockCount; i++ {
 					// first decode the key string
-					if value, buf, err = stringNativeFromBinary(buf); err != nil {
-						return nil, nil, fmt.Errorf("cannot decode binary map key: %s", err)
+					var key string
+					if key, buf, err = keyFromBinary(buf); err != nil {
+						return nil, nil, fmt.Errorf("cannot decode binary map key: %w", err)
 					}
-					key := value.(string) // string decoder always returns a string
 					if _, ok := mapValues[key]; ok {
 						return nil, nil, fmt.Errorf("cannot decode binary map: duplicate key: %q", key)
 					}
 					// then decode the value
 					if value, buf, err = valueCodec.nativeFromBinary(buf); err != nil {
-						return nil, nil, fmt.Errorf("cannot decode binary map key %q value: %s", key, err)
+						return nil, nil, asDecodeError(err, valueCodec).within(key).prefixed("cannot decode binary map key %q value", key)
 					}
 					mapValues[key] = value
 				}
 				// Decode next blockCount from buffer, because there may be more blocks
-				if value, buf, err = longNativeFromBinary(buf); err != nil {
-					return nil, nil, fmt.Errorf("cannot decode binary map block count: %s", err)
+				if blockCount, buf, err = longFromBinary(buf); err != nil {
+					return nil, nil, fmt.Errorf("cannot decode binary map block count: %w", err)
 				}
-				blockCount = value.(int64)
 				if blockCount < 0 {
 					// NOTE: A negative block count implies there is a long
-					// encoded block size following the negative block count. We
-					// have no use for the block size in this decoder, so we
-					// read and discard the value.
+					// encoded block size following the negative block count.
+					// This decoder only uses the block size to reject blocks
+					// larger than MaxBlockSize.
 					if blockCount == math.MinInt64 {
 						// The minimum number for any signed numerical type can
 						// never be made positive
 						return nil, nil, fmt.Errorf("cannot decode binary map with block count: %d", math.MinInt64)
 					}
 					blockCount = -blockCount // convert to its positive equivalent
-					if _, buf, err = longNativeFromBinary(buf); err != nil {
-						return nil, nil, fmt.Errorf("cannot decode binary map block size: %s", err)
+					if blockSize, buf, err = longFromBinary(buf); err != nil {
+						return nil, nil, fmt.Errorf("cannot decode binary map block size: %w", err)
+					}
+					if blockSize > maxBlockSize {
+						return nil, nil, fmt.Errorf("cannot decode binary map when block size exceeds MaxBlockSize: %d > %d", blockSize, maxBlockSize)
 					}
 				}
 				// Ensure block count does not exceed some sane value.
-				if blockCount > MaxBlockCount {
-					return nil, nil, fmt.Errorf("cannot decode binary map when block count exceeds MaxBlockCount: %d > %d", blockCount, MaxBlockCount)
+				if blockCount > maxBlockCount {
+					return nil, nil, fmt.Errorf("cannot decode binary map when block count exceeds MaxBlockCount: %d > %d", blockCount, maxBlockCount)
 				}
 			}
 			return mapValues, buf, nil
 		},
+		skipBinary: func(buf []byte) ([]byte, error) {
+			var blockCount, blockSize int64
+			var err error
+			for {
+				if blockCount, buf, err = longFromBinary(buf); err != nil {
+					return nil, fmt.Errorf("cannot skip binary map block count: %w", err)
+				}
+				if blockCount == 0 {
+					return buf, nil
+				}
+				if blockCount < 0 {
+					// NOTE: A negative block count implies there is a long
+					// encoded block size following the negative block count,
+					// which allows skipping the entire block at once.
+					if blockCount == math.MinInt64 {
+						// The minimum number for any signed numerical type can
+						// never be made positive
+						return nil, fmt.Errorf("cannot skip binary map with block count: %d", math.MinInt64)
+					}
+					if blockSize, buf, err = longFromBinary(buf); err != nil {
+						return nil, fmt.Errorf("cannot skip binary map block size: %w", err)
+					}
+					if blockSize < 0 || blockSize > int64(len(buf)) {
+						return nil, fmt.Errorf("cannot skip binary map block size: %d: %w", blockSize, io.ErrShortBuffer)
+					}
+					buf = buf[blockSize:]
+					continue
+				}
+				// Ensure block count does not exceed some sane value.
+				if maxBlockCount := config.blockCountLimit(); blockCount > maxBlockCount {
+					return nil, fmt.Errorf("cannot skip binary map when block count exceeds MaxBlockCount: %d > %d", blockCount, maxBlockCount)
+				}
+				for i := int64(0); i < blockCount; i++ {
+					start := buf
+					if buf, err = stringSkipBinary(buf); err != nil {
+						return nil, fmt.Errorf("cannot skip binary map key: %w", err)
+					}
+					if buf, err = valueCodec.skipBinary(buf); err != nil {
+						key, _, _ := stringFromBinary(start) // already known to be valid
+						return nil, asDecodeError(err, valueCodec).within(key).prefixed("cannot skip binary map value")
+					}
+				}
+			}
+		},
 		binaryFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
+			if next, ok := entryProducer(datum); ok {
+				return appendProducedEntries(nil, buf, valueCodec, config, next)
+			}
 			mapValues, err := convertMap(datum)
 			if err != nil {
 				return nil, fmt.Errorf("cannot encode binary map: %s", err)
 			}
 
-			keyCount := int64(len(mapValues))
-			var alreadyEncoded, remainingInBlock int64
-
-			for k, v := range mapValues {
-				if remainingInBlock == 0 { // start a new block
-					remainingInBlock = keyCount - alreadyEncoded
-					if remainingInBlock > MaxBlockCount {
-						// limit block count to MacBlockCount
-						remainingInBlock = MaxBlockCount
+			entries := mapEntryEncoder{valueCodec: valueCodec, blocks: newBlockEncoder(config, int64(len(mapValues)))}
+			if config.sortedMapKeys {
+				for _, k := range sortedMapKeys(mapValues) {
+					if buf, err = entries.append(buf, k, mapValues[k]); err != nil {
+						return nil, err
 					}
-					buf, _ = longBinaryFromNative(buf, remainingInBlock)
 				}
-
-				// only fails when given non string, so elide error checking
-				buf, _ = stringBinaryFromNative(buf, k)
-
-				// encode the value
-				if buf, err = valueCodec.binaryFromNative(buf, v); err != nil {
-					return nil, fmt.Errorf("cannot encode binary map value for key %q: %v: %s", k, v, err)
+			} else {
+				for k, v := range mapValues {
+					if buf, err = entries.append(buf, k, v); err != nil {
+						return nil, err
+					}
 				}
-
-				remainingInBlock--
-				alreadyEncoded++
 			}
-			return longBinaryFromNative(buf, 0) // append tailing 0 block count to signal end of Map
+			return append(buf, 0), nil // append tailing 0 block count to signal end of Map
 		},
 		nativeFromTextual: func(buf []byte) (interface{}, []byte, error) {
 			return genericMapTextDecoder(buf, valueCodec, nil) // codecFromKey == nil
 		},
 		textualFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
-			return genericMapTextEncoder(buf, datum, valueCodec, nil)
+			return genericMapTextEncoder(buf, datum, valueCodec, nil, config.sortedMapKeys)
 		},
-	}, nil
+	}
+	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
+		return c.binaryInto(buf, nil)
+	}
+	c.node = &MapSchema{c: c, values: valueCodec.node}
+	return c
+}
+
+// mapEntryEncoder appends the binary encoding of the entries of a map, one at
+// a time, in blocks.
+type mapEntryEncoder struct {
+	valueCodec *Codec
+	blocks     blockEncoder
+}
+
+func (e *mapEntryEncoder) append(buf []byte, k string, v interface{}) ([]byte, error) {
+	buf = e.blocks.begin(buf)
+
+	// only fails when given non string, so elide error checking
+	buf, _ = stringBinaryFromNative(buf, k)
+
+	// encode the value
+	var err error
+	if buf, err = e.valueCodec.binaryFromNative(buf, v); err != nil {
+		return nil, asEncodeError(err, e.valueCodec, v).within(k).prefixed("cannot encode binary map value for key %q: %v", k, v)
+	}
+
+	return e.blocks.end(buf), nil
+}
+
+// sortedMapKeys returns the keys of mapValues in sorted order.
+func sortedMapKeys(mapValues map[string]interface{}) []string {
+	keys := make([]string, 0, len(mapValues))
+	for key := range mapValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // genericMapTextDecoder decodes a JSON text blob to a native Go map, using the
@@ -170,7 +268,7 @@ func genericMapTextDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[str
 		// decode key string
 		value, buf, err = stringNativeFromTextual(buf)
 		if err != nil {
-			return nil, nil, fmt.Errorf("cannot decode textual map: expected key: %s", err)
+			return nil, nil, fmt.Errorf("cannot decode textual map: expected key: %w", err)
 		}
 		key := value.(string)
 		// Is key already used?
@@ -195,7 +293,7 @@ func genericMapTextDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[str
 		}
 		value, buf, err = fieldCodec.nativeFromTextual(buf)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, asDecodeError(err, fieldCodec).within(key)
 		}
 		// set map value for key
 		mapValues[key] = value
@@ -224,8 +322,8 @@ func genericMapTextDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[str
 // defaultCodec if provided. If defaultCodec is nil, this function returns an
 // error if it encounters a map key that is not present in codecFromKey. If
 // codecFromKey is nil, every map value will be encoded using defaultCodec, if
-// possible.
-func genericMapTextEncoder(buf []byte, datum interface{}, defaultCodec *Codec, codecFromKey map[string]*Codec) ([]byte, error) {
+// possible. When sortKeys is true, the keys are encoded in sorted order.
+func genericMapTextEncoder(buf []byte, datum interface{}, defaultCodec *Codec, codecFromKey map[string]*Codec, sortKeys bool) ([]byte, error) {
 	mapValues, err := convertMap(datum)
 	if err != nil {
 		return nil, fmt.Errorf("cannot encode textual map: %s", err)
@@ -235,7 +333,7 @@ func genericMapTextEncoder(buf []byte, datum interface{}, defaultCodec *Codec, c
 
 	buf = append(buf, '{')
 
-	for key, value := range mapValues {
+	appendEntry := func(key string, value interface{}) error {
 		atLeastOne = true
 
 		// Find a codec for the key
@@ -244,21 +342,36 @@ func genericMapTextEncoder(buf []byte, datum interface{}, defaultCodec *Codec, c
 			fieldCodec = defaultCodec
 		}
 		if fieldCodec == nil {
-			return nil, fmt.Errorf("cannot encode textual map: cannot determine codec: %q", key)
+			return fmt.Errorf("cannot encode textual map: cannot determine codec: %q", key)
 		}
 		// Encode key string
 		buf, err = stringTextualFromNative(buf, key)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		buf = append(buf, ':')
 		// Encode value
 		buf, err = fieldCodec.textualFromNative(buf, value)
 		if err != nil {
 			// field was specified in datum; therefore its value was invalid
-			return nil, fmt.Errorf("cannot encode textual map: value for %q does not match its schema: %s", key, err)
+			return asEncodeError(err, fieldCodec, value).within(key).prefixed("cannot encode textual map: value for %q does not match its schema", key)
 		}
 		buf = append(buf, ',')
+		return nil
+	}
+
+	if sortKeys {
+		for _, key := range sortedMapKeys(mapValues) {
+			if err = appendEntry(key, mapValues[key]); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for key, value := range mapValues {
+			if err = appendEntry(key, value); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if atLeastOne {
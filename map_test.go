@@ -1,6 +1,7 @@
 package goavro_test
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"testing"
@@ -166,3 +167,64 @@ func ExampleMap() {
 	fmt.Println(string(buf))
 	// Output: {"f1":{"k1":3.5}}
 }
+
+func TestMapSortedKeys(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"record","name":"r1","fields":[{"name":"m","type":{"type":"map","values":"int"}},{"name":"a","type":"int"}]}`,
+		goavro.WithSortedMapKeys(), goavro.WithMaxBlockCount(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := map[string]interface{}{
+		"m": map[string]int{"c": 3, "a": 1, "d": 4, "b": 2},
+		"a": 5,
+	}
+	expected := []byte{
+		4, 2, 'a', 2, 2, 'b', 4, // first block
+		4, 2, 'c', 6, 2, 'd', 8, // second block
+		0,  // end of map
+		10, // field a
+	}
+	for i := 0; i < 10; i++ {
+		buf, err := codec.BinaryFromNative(nil, datum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf, expected) {
+			t.Fatalf("Actual: %#v; Expected: %#v", buf, expected)
+		}
+		buf, err = codec.TextualFromNative(nil, datum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := string(buf), `{"a":5,"m":{"a":1,"b":2,"c":3,"d":4}}`; actual != expected {
+			t.Fatalf("Actual: %s; Expected: %s", actual, expected)
+		}
+	}
+}
+
+func TestMapSizedBlocks(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"map","values":"int"}`, goavro.WithSizedBlocks(2), goavro.WithSortedMapKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := map[string]interface{}{"a": int32(1), "b": int32(2), "c": int32(3)}
+	buf, err := codec.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{
+		3, 12, 2, 'a', 2, 2, 'b', 4, // block of 2 entries using 6 bytes
+		1, 6, 2, 'c', 6, // block of 1 entry using 3 bytes
+		0, // end of map
+	}
+	if !bytes.Equal(buf, expected) {
+		t.Fatalf("Actual: %#v; Expected: %#v", buf, expected)
+	}
+	decoded, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", decoded), "map[a:1 b:2 c:3]"; actual != expected {
+		t.Errorf("Actual: %s; Expected: %s", actual, expected)
+	}
+}
@@ -0,0 +1,297 @@
+package goavro
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"reflect"
+)
+
+// MaskFunc returns the value that replaces a masked field value. It is provided
+// the schema of the value, which for union fields is the schema of the union
+// member holding the value, along with the value itself. Null union values are
+// never masked.
+type MaskFunc func(schema Schema, value interface{}) (interface{}, error)
+
+// MaskRule selects record fields whose values are replaced by a Masker.
+type MaskRule struct {
+	// Path, when not empty, selects the field at the specified field path,
+	// for instance "/order/customer/email". Each name in a field path selects
+	// a field of the record reached so far, while array items, map values, and
+	// union members are descended into without naming them.
+	Path string
+
+	// Property, when not empty, selects every field having the specified
+	// custom property set to Value, for instance `"pii": true`. When both
+	// Path and Property are provided, a field must match both.
+	Property string
+
+	// Value is compared with the value of the custom property named by
+	// Property, in the form returned by json.Unmarshal.
+	Value interface{}
+
+	// Mask returns the replacement value for each selected field value.
+	Mask MaskFunc
+}
+
+// Masker decodes binary Avro data, replaces the values of the record fields
+// selected by its rules, and encodes the result using the same schema. Like a
+// Codec, a Masker may be safely used by many go routines simultaneously.
+type Masker struct {
+	codec *Codec
+	rules []MaskRule
+}
+
+// NewMasker returns a Masker that masks data encoded using the schema of the
+// provided Codec, in accordance with the provided rules. It returns an error
+// when a rule does not have a Mask function, or selects no field of the schema.
+//
+//     masker, err := goavro.NewMasker(codec,
+//             goavro.MaskRule{Property: "pii", Value: true, Mask: goavro.Hash(sha256.New)},
+//             goavro.MaskRule{Path: "/address/street", Mask: goavro.Redact("REDACTED")},
+//     )
+//     if err != nil {
+//             fmt.Println(err)
+//     }
+//     masked, _, err := masker.MaskBinary(nil, binary)
+func NewMasker(codec *Codec, rules ...MaskRule) (*Masker, error) {
+	for i, rule := range rules {
+		if rule.Mask == nil {
+			return nil, fmt.Errorf("cannot create Masker: rule %d ought to have Mask function", i+1)
+		}
+		if rule.Path == "" && rule.Property == "" {
+			return nil, fmt.Errorf("cannot create Masker: rule %d ought to have Path or Property", i+1)
+		}
+		if rule.Path != "" {
+			if _, err := resolveFieldPath(codec.node, rule.Path); err != nil {
+				return nil, fmt.Errorf("cannot create Masker: rule %d: %s", i+1, err)
+			}
+		}
+	}
+	return &Masker{codec: codec, rules: append([]MaskRule(nil), rules...)}, nil
+}
+
+// MaskBinary decodes one datum from src, masks it, and appends its binary
+// encoding to dst. On success, it returns the new dst byte slice, the src byte
+// slice with the decoded bytes consumed, and a nil error value. On error, it
+// returns the original dst and src byte slices, and the error message.
+func (m *Masker) MaskBinary(dst, src []byte) ([]byte, []byte, error) {
+	datum, remaining, err := m.codec.NativeFromBinary(src)
+	if err != nil {
+		return dst, src, err
+	}
+	if datum, err = m.mask(m.codec.node, "", datum); err != nil {
+		return dst, src, err
+	}
+	newDst, err := m.codec.BinaryFromNative(dst, datum)
+	if err != nil {
+		return dst, src, fmt.Errorf("cannot encode masked datum: %s", err)
+	}
+	return newDst, remaining, nil
+}
+
+// MaskNative returns a copy of the native datum with the values of the selected
+// record fields replaced. The provided datum is not modified.
+func (m *Masker) MaskNative(datum interface{}) (interface{}, error) {
+	return m.mask(m.codec.node, "", datum)
+}
+
+// maskFuncFor returns the MaskFunc of the first rule selecting the field at the
+// specified field path, or nil when no rule selects it.
+func (m *Masker) maskFuncFor(field *FieldSchema, path string) MaskFunc {
+	for _, rule := range m.rules {
+		if rule.Path != "" && rule.Path != path {
+			continue
+		}
+		if rule.Property != "" {
+			value, ok := field.properties[rule.Property]
+			if !ok || !reflect.DeepEqual(value, rule.Value) {
+				continue
+			}
+		}
+		return rule.Mask
+	}
+	return nil
+}
+
+// mask returns a copy of datum, described by schema s, whose record fields are
+// masked in accordance with the rules of the Masker. The field path of datum
+// is provided so fields within it may be matched against rules.
+func (m *Masker) mask(s Schema, path string, datum interface{}) (interface{}, error) {
+	switch v := s.(type) {
+	case *RecordSchema:
+		sourceMap, ok := datum.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot mask record %q: expected map[string]interface{}; received: %T", v.Name(), datum)
+		}
+		destMap := make(map[string]interface{}, len(sourceMap))
+		for key, value := range sourceMap {
+			destMap[key] = value
+		}
+		for _, field := range v.fields {
+			value, ok := sourceMap[field.name]
+			if !ok {
+				continue // encoder will use the default value of the field
+			}
+			fieldPath := path + "/" + field.name
+			var err error
+			if maskFunc := m.maskFuncFor(field, fieldPath); maskFunc != nil {
+				value, err = maskValue(field.schema, value, maskFunc)
+			} else {
+				value, err = m.mask(field.schema, fieldPath, value)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("cannot mask record %q field %q: %s", v.Name(), field.name, err)
+			}
+			destMap[field.name] = value
+		}
+		return destMap, nil
+	case *ArraySchema:
+		if len(recordsWithin(v.items)) == 0 {
+			return datum, nil // no fields within items to mask
+		}
+		arrayValues, err := convertArray(datum)
+		if err != nil {
+			return nil, fmt.Errorf("cannot mask array: %s", err)
+		}
+		masked := make([]interface{}, len(arrayValues))
+		for i, item := range arrayValues {
+			if masked[i], err = m.mask(v.items, path, item); err != nil {
+				return nil, fmt.Errorf("cannot mask array item %d: %s", i+1, err)
+			}
+		}
+		return masked, nil
+	case *MapSchema:
+		if len(recordsWithin(v.values)) == 0 {
+			return datum, nil // no fields within values to mask
+		}
+		mapValues, err := convertMap(datum)
+		if err != nil {
+			return nil, fmt.Errorf("cannot mask map: %s", err)
+		}
+		masked := make(map[string]interface{}, len(mapValues))
+		for key, value := range mapValues {
+			if masked[key], err = m.mask(v.values, path, value); err != nil {
+				return nil, fmt.Errorf("cannot mask map key %q value: %s", key, err)
+			}
+		}
+		return masked, nil
+	case *UnionSchema:
+		member, value, wrap, err := unionMember(v, datum)
+		if err != nil || member == nil {
+			return datum, err
+		}
+		if value, err = m.mask(member, path, value); err != nil {
+			return nil, err
+		}
+		return wrap(value), nil
+	}
+	return datum, nil
+}
+
+// maskValue returns the value replacing a field value described by schema s.
+// The values of union fields are unwrapped before invoking maskFunc, and the
+// replacement is wrapped using the same union member.
+func maskValue(s Schema, datum interface{}, maskFunc MaskFunc) (interface{}, error) {
+	u, ok := s.(*UnionSchema)
+	if !ok {
+		return maskFunc(s, datum)
+	}
+	member, value, wrap, err := unionMember(u, datum)
+	if err != nil || member == nil {
+		return datum, err
+	}
+	if value, err = maskFunc(member, value); err != nil {
+		return nil, err
+	}
+	return wrap(value), nil
+}
+
+// unionMember returns the member schema and the value of a union datum, along
+// with a function that wraps a replacement value the same way datum wraps its
+// value, whether in a map naming the member, in a UnionValue, or not at all. It
+// returns a nil member for null values, and an error when no member of the
+// union encodes datum, so values it cannot resolve are never left unmasked.
+func unionMember(u *UnionSchema, datum interface{}) (Schema, interface{}, func(interface{}) interface{}, error) {
+	if datum == nil {
+		return nil, nil, nil, nil
+	}
+	index, value, err := u.memberOf(datum)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot mask union: %s", err)
+	}
+	member := u.members[index]
+	if value == nil {
+		return nil, nil, nil, nil
+	}
+	switch v := datum.(type) {
+	case UnionValue:
+		return member, value, func(value interface{}) interface{} {
+			return UnionValue{Index: index, Name: v.Name, Value: value}
+		}, nil
+	case map[string]interface{}:
+		if !sameMap(v, value) {
+			name := member.codec().typeName.fullName
+			return member, value, func(value interface{}) interface{} { return Union(name, value) }, nil
+		}
+	}
+	return member, value, func(value interface{}) interface{} { return value }, nil
+}
+
+// sameMap returns true when value is the map m itself, rather than a value
+// wrapped by it.
+func sameMap(m map[string]interface{}, value interface{}) bool {
+	other, ok := value.(map[string]interface{})
+	return ok && reflect.ValueOf(m).Pointer() == reflect.ValueOf(other).Pointer()
+}
+
+// Redact returns a MaskFunc that replaces each selected value with the provided
+// replacement value, which ought to be valid for the schema of the field.
+func Redact(replacement interface{}) MaskFunc {
+	return func(_ Schema, _ interface{}) (interface{}, error) {
+		return copyNative(replacement), nil
+	}
+}
+
+// Hash returns a MaskFunc that replaces each selected value with its digest,
+// computed using a hash.Hash returned by newHash, such as sha256.New, or a
+// function returning an HMAC for keyed hashing. Strings are replaced by the
+// hexadecimal representation of their digest, bytes by their digest, and fixed
+// values by their digest truncated to the size of the fixed type. Values of
+// other types cannot be hashed.
+func Hash(newHash func() hash.Hash) MaskFunc {
+	return func(s Schema, datum interface{}) (interface{}, error) {
+		h := newHash()
+		switch v := s.(type) {
+		case *PrimitiveSchema:
+			switch v.typeName {
+			case "string":
+				someString, ok := datum.(string)
+				if !ok {
+					return nil, fmt.Errorf("cannot hash string: received: %T", datum)
+				}
+				_, _ = h.Write([]byte(someString)) // hash.Hash never returns an error
+				return hex.EncodeToString(h.Sum(nil)), nil
+			case "bytes":
+				someBytes, ok := datum.([]byte)
+				if !ok {
+					return nil, fmt.Errorf("cannot hash bytes: received: %T", datum)
+				}
+				_, _ = h.Write(someBytes)
+				return h.Sum(nil), nil
+			}
+		case *FixedSchema:
+			someBytes, ok := datum.([]byte)
+			if !ok {
+				return nil, fmt.Errorf("cannot hash fixed %q: received: %T", v.Name(), datum)
+			}
+			if h.Size() < v.Size() {
+				return nil, fmt.Errorf("cannot hash fixed %q: digest size ought to be at least fixed size: %d < %d", v.Name(), h.Size(), v.Size())
+			}
+			_, _ = h.Write(someBytes)
+			return h.Sum(nil)[:v.Size()], nil
+		}
+		return nil, errors.New("cannot hash " + s.Type() + ": only string, bytes, and fixed values may be hashed")
+	}
+}
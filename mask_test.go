@@ -0,0 +1,226 @@
+package goavro_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+const maskTestSchema = `
+{"type": "record", "name": "Order",
+ "fields": [
+     {"name": "id", "type": "long"},
+     {"name": "customer", "type": {"type": "record", "name": "Customer",
+        "fields": [
+            {"name": "email", "type": "string", "pii": true},
+            {"name": "phone", "type": ["null", "string"], "pii": true},
+            {"name": "street", "type": "string"},
+            {"name": "token", "type": {"type": "fixed", "name": "Token", "size": 4}, "pii": true}
+        ]}},
+     {"name": "notes", "type": {"type": "array", "items": {"type": "record", "name": "Note",
+        "fields": [{"name": "text", "type": "string"}]}}}
+ ]
+}`
+
+func newMaskTestDatum() map[string]interface{} {
+	return map[string]interface{}{
+		"id": int64(42),
+		"customer": map[string]interface{}{
+			"email":  "ann@example.com",
+			"phone":  goavro.Union("string", "555-1212"),
+			"street": "1 Main St",
+			"token":  []byte("abcd"),
+		},
+		"notes": []interface{}{
+			map[string]interface{}{"text": "first"},
+			map[string]interface{}{"text": "second"},
+		},
+	}
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestMaskerBinary(t *testing.T) {
+	codec, err := goavro.NewCodec(maskTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masker, err := goavro.NewMasker(codec,
+		goavro.MaskRule{Property: "pii", Value: true, Mask: goavro.Hash(sha256.New)},
+		goavro.MaskRule{Path: "/customer/street", Mask: goavro.Redact("REDACTED")},
+		goavro.MaskRule{Path: "/notes/text", Mask: goavro.Redact("")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binary, err := codec.BinaryFromNative(nil, newMaskTestDatum())
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary = append(binary, 0xFF) // ensure remaining bytes are returned
+
+	masked, remaining, err := masker.MaskBinary([]byte("prefix"), binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := string(remaining), "\xFF"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := string(masked[:6]), "prefix"; actual != expected {
+		t.Fatalf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	datum, _, err := codec.NativeFromBinary(masked[6:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	customer := datum.(map[string]interface{})["customer"].(map[string]interface{})
+	if actual, expected := customer["email"], sha256Hex("ann@example.com"); actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := fmt.Sprintf("%v", customer["phone"]), fmt.Sprintf("map[string:%s]", sha256Hex("555-1212")); actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := customer["street"], "REDACTED"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	sum := sha256.Sum256([]byte("abcd"))
+	if actual, expected := fmt.Sprintf("%x", customer["token"]), fmt.Sprintf("%x", sum[:4]); actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := fmt.Sprintf("%v", datum.(map[string]interface{})["notes"]), "[map[text:] map[text:]]"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := datum.(map[string]interface{})["id"], int64(42); actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
+
+func TestMaskerNativeDoesNotModifyDatum(t *testing.T) {
+	codec, err := goavro.NewCodec(maskTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := []byte("secret")
+	masker, err := goavro.NewMasker(codec, goavro.MaskRule{Path: "/customer/email", Mask: goavro.Hash(func() hash.Hash { return hmac.New(sha256.New, key) })})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := newMaskTestDatum()
+	datum["customer"].(map[string]interface{})["phone"] = nil
+
+	masked, err := masker.MaskNative(datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("ann@example.com"))
+	customer := masked.(map[string]interface{})["customer"].(map[string]interface{})
+	if actual, expected := customer["email"], hex.EncodeToString(mac.Sum(nil)); actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual := customer["phone"]; actual != nil {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, nil)
+	}
+	if actual, expected := datum["customer"].(map[string]interface{})["email"], "ann@example.com"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
+
+func TestMaskerInvalidRules(t *testing.T) {
+	codec, err := goavro.NewCodec(maskTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = goavro.NewMasker(codec, goavro.MaskRule{Path: "/customer/email"})
+	ensureError(t, err, "rule 1 ought to have Mask function")
+	_, err = goavro.NewMasker(codec, goavro.MaskRule{Mask: goavro.Redact("")})
+	ensureError(t, err, "rule 1 ought to have Path or Property")
+	_, err = goavro.NewMasker(codec, goavro.MaskRule{Path: "/customer/fax", Mask: goavro.Redact("")})
+	ensureError(t, err, `cannot find field path: "/customer/fax"`)
+	_, err = goavro.NewMasker(codec, goavro.MaskRule{Path: "customer", Mask: goavro.Redact("")})
+	ensureError(t, err, "field path ought to start with /")
+}
+
+func TestMaskerHashUnsupportedType(t *testing.T) {
+	codec, err := goavro.NewCodec(maskTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masker, err := goavro.NewMasker(codec, goavro.MaskRule{Path: "/id", Mask: goavro.Hash(sha256.New)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = masker.MaskNative(newMaskTestDatum())
+	ensureError(t, err, `cannot mask record "Order" field "id": cannot hash long`)
+}
+
+func TestMaskerUnionRepresentations(t *testing.T) {
+	for _, tc := range []struct {
+		option   goavro.CodecOption
+		phone    interface{}
+		expected interface{}
+	}{
+		{goavro.WithBareNullableUnions(), "555-1212", sha256Hex("555-1212")},
+		{goavro.WithUnionValues(), goavro.UnionValue{Index: 1, Name: "string", Value: "555-1212"}, goavro.UnionValue{Index: 1, Name: "string", Value: sha256Hex("555-1212")}},
+	} {
+		codec, err := goavro.NewCodecWithOptions(maskTestSchema, tc.option)
+		if err != nil {
+			t.Fatal(err)
+		}
+		masker, err := goavro.NewMasker(codec, goavro.MaskRule{Property: "pii", Value: true, Mask: goavro.Hash(sha256.New)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		datum := newMaskTestDatum()
+		datum["customer"].(map[string]interface{})["phone"] = tc.phone
+
+		masked, err := masker.MaskNative(datum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := masked.(map[string]interface{})["customer"].(map[string]interface{})["phone"]; actual != tc.expected {
+			t.Errorf("Actual: %#v; Expected: %#v", actual, tc.expected)
+		}
+
+		binary, err := codec.BinaryFromNative(nil, datum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		binary, _, err = masker.MaskBinary(nil, binary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, _, err := codec.NativeFromBinary(binary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := decoded.(map[string]interface{})["customer"].(map[string]interface{})["phone"]; actual != tc.expected {
+			t.Errorf("Actual: %#v; Expected: %#v", actual, tc.expected)
+		}
+	}
+}
+
+func TestMaskerInvalidUnionValue(t *testing.T) {
+	codec, err := goavro.NewCodec(maskTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masker, err := goavro.NewMasker(codec, goavro.MaskRule{Property: "pii", Value: true, Mask: goavro.Hash(sha256.New)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := newMaskTestDatum()
+	datum["customer"].(map[string]interface{})["phone"] = "555-1212" // not wrapped
+	_, err = masker.MaskNative(datum)
+	ensureError(t, err, `cannot mask record "Customer" field "phone": cannot mask union`)
+}
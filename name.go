@@ -81,17 +81,48 @@ func newName(n, ns, ens string) (*name, error) {
 	}
 
 	// verify all components of the full name for adherence to Avro naming rules
-	for _, component := range strings.Split(nn.fullName, ".") {
+	if err := CheckFullName(nn.fullName); err != nil {
+		return nil, err
+	}
+
+	return &nn, nil
+}
+
+// CheckName returns an ErrInvalidName when name, which has no namespace, such
+// as a record field name or an enum symbol, does not follow the Avro naming
+// rules.
+func CheckName(name string) error {
+	return checkNameComponent(name)
+}
+
+// CheckFullName returns an ErrInvalidName when fullName, which may include a
+// namespace, does not follow the Avro naming rules.
+func CheckFullName(fullName string) error {
+	for _, component := range strings.Split(fullName, ".") {
 		if err := checkNameComponent(component); err != nil {
-			return nil, err
+			return err
 		}
 	}
+	return nil
+}
 
-	return &nn, nil
+// CheckTypeName returns an ErrInvalidName when fullName, which includes the
+// namespace if any, is not a valid full name of a named type: when it does not
+// follow the Avro naming rules, or would redefine the name of a primitive type,
+// or of a complex type in the null namespace.
+func CheckTypeName(fullName string) error {
+	if err := CheckFullName(fullName); err != nil {
+		return err
+	}
+	return checkTypeName(&name{fullName: fullName})
 }
 
+// newNameFromSchemaMap returns the name of the named type described by
+// schemaMap, defined within enclosingNamespace. As specified by Avro, a
+// namespace attribute replaces the enclosing namespace, and an empty namespace
+// attribute denotes the null namespace.
 func newNameFromSchemaMap(enclosingNamespace string, schemaMap map[string]interface{}) (*name, error) {
-	var nameString, namespaceString string
+	var nameString string
 
 	name, ok := schemaMap["name"]
 	if !ok {
@@ -101,15 +132,68 @@ func newNameFromSchemaMap(enclosingNamespace string, schemaMap map[string]interf
 	if !ok || nameString == nullNamespace {
 		return nil, fmt.Errorf("schema name ought to be non-empty string; received: %T", name)
 	}
-	namespace, ok := schemaMap["namespace"]
-	if ok {
-		namespaceString, ok = namespace.(string)
-		if !ok || namespaceString == nullNamespace {
-			return nil, fmt.Errorf("schema namespace, if provided, ought to be non-empty string; received: %T", namespace)
+	if namespace, ok := schemaMap["namespace"]; ok {
+		namespaceString, ok := namespace.(string)
+		if !ok {
+			return nil, fmt.Errorf("schema namespace, if provided, ought to be string; received: %T", namespace)
 		}
+		enclosingNamespace = namespaceString
 	}
 
-	return newName(nameString, namespaceString, enclosingNamespace)
+	return newName(nameString, nullNamespace, enclosingNamespace)
+}
+
+// primitiveTypeNames are the names of the primitive types, which named types
+// may not use in any namespace.
+var primitiveTypeNames = map[string]struct{}{
+	"null": {}, "boolean": {}, "int": {}, "long": {}, "float": {}, "double": {}, "bytes": {}, "string": {},
+}
+
+// complexTypeNames are the names of the complex types, which named types may
+// not use in the null namespace, where references to them would be ambiguous.
+var complexTypeNames = map[string]struct{}{
+	"array": {}, "enum": {}, "fixed": {}, "map": {}, "record": {},
+}
+
+// checkTypeName returns an error when a named type would redefine the name of
+// a primitive or complex type.
+func checkTypeName(n *name) error {
+	if _, ok := primitiveTypeNames[n.short()]; ok {
+		return &ErrInvalidName{"not redefine primitive type: " + n.fullName}
+	}
+	if _, ok := complexTypeNames[n.fullName]; ok {
+		return &ErrInvalidName{"not redefine complex type: " + n.fullName}
+	}
+	return nil
+}
+
+// fieldNameFromSchemaMap returns the name of the record field described by
+// schemaMap. Unlike the names of named types, field names have no namespace,
+// and ought not contain periods.
+func fieldNameFromSchemaMap(schemaMap map[string]interface{}) (string, error) {
+	name, ok := schemaMap["name"]
+	if !ok {
+		return "", errors.New("schema ought to have name key")
+	}
+	nameString, ok := name.(string)
+	if !ok {
+		return "", fmt.Errorf("schema name ought to be non-empty string; received: %T", name)
+	}
+	if err := CheckName(nameString); err != nil {
+		return "", err
+	}
+	return nameString, nil
+}
+
+// candidateFullNames returns the full names a reference to typeName from
+// within enclosingNamespace may denote, in the order the Avro specification
+// resolves them: a name without periods denotes a type in the enclosing
+// namespace, or failing that, a type in the null namespace.
+func candidateFullNames(typeName, enclosingNamespace string) []string {
+	if enclosingNamespace == nullNamespace || strings.IndexByte(typeName, '.') > -1 {
+		return []string{typeName}
+	}
+	return []string{enclosingNamespace + "." + typeName, typeName}
 }
 
 func (n *name) String() string {
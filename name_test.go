@@ -3,6 +3,9 @@ package goavro
 // NOTE: part of goavro package because it tests private functionality
 
 import (
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -20,6 +23,34 @@ func TestNameContainsInvalidCharacter(t *testing.T) {
 	}
 }
 
+func TestCheckTypeName(t *testing.T) {
+	cases := []struct {
+		fullName string
+		err      string
+	}{
+		{fullName: "com.acme.User"},
+		{fullName: "com.acme.record"},
+		{fullName: "User.", err: "be non-empty string"},
+		{fullName: "com.acme.long", err: "not redefine primitive type"},
+		{fullName: "record", err: "not redefine complex type"},
+	}
+	for _, c := range cases {
+		err := CheckTypeName(c.fullName)
+		if c.err == "" {
+			if err != nil {
+				t.Errorf("%q: %s", c.fullName, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%q: Actual: %v; Expected: %s", c.fullName, err, c.err)
+		}
+	}
+	if err := CheckName("com.acme"); err == nil {
+		t.Errorf("Actual: %v; Expected: error", err)
+	}
+}
+
 func TestNameAndNamespaceProvided(t *testing.T) {
 	n, err := newName("X", "org.foo", nullNamespace)
 	if err != nil {
@@ -58,3 +89,154 @@ func TestNameWithoutDotsButWithEmptyNamespaceAndEnclosingName(t *testing.T) {
 		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
 	}
 }
+
+// TestNameResolutionConformance checks the full names of the named types a
+// schema defines, and the types its record fields refer to, against the name
+// resolution rules of the Avro specification, as implemented by Java.
+func TestNameResolutionConformance(t *testing.T) {
+	cases := []struct {
+		description string
+		schema      string
+		names       []string          // full names of the named types defined by the schema
+		fields      map[string]string // full name of the type of each field of the root record
+		err         string
+	}{
+		{
+			description: "nested types inherit the namespace of the enclosing name",
+			schema:      `{"type":"record","name":"a.b.R","fields":[{"name":"f","type":{"type":"enum","name":"E","symbols":["X"]}}]}`,
+			names:       []string{"a.b.E", "a.b.R"},
+			fields:      map[string]string{"f": "a.b.E"},
+		},
+		{
+			description: "namespace attribute applies to nested types",
+			schema:      `{"type":"record","name":"R","namespace":"x","fields":[{"name":"f","type":{"type":"fixed","name":"F","size":1}}]}`,
+			names:       []string{"x.F", "x.R"},
+			fields:      map[string]string{"f": "x.F"},
+		},
+		{
+			description: "dotted name ignores namespace attribute",
+			schema:      `{"type":"record","name":"a.R","namespace":"b","fields":[{"name":"f","type":{"type":"fixed","name":"F","size":1}}]}`,
+			names:       []string{"a.F", "a.R"},
+			fields:      map[string]string{"f": "a.F"},
+		},
+		{
+			description: "nested namespace attribute replaces enclosing namespace",
+			schema:      `{"type":"record","name":"R","namespace":"x","fields":[{"name":"f","type":{"type":"fixed","name":"F","namespace":"y","size":1}}]}`,
+			names:       []string{"x.R", "y.F"},
+			fields:      map[string]string{"f": "y.F"},
+		},
+		{
+			description: "empty namespace attribute denotes null namespace",
+			schema:      `{"type":"record","name":"R","namespace":"x","fields":[{"name":"f","type":{"type":"fixed","name":"F","namespace":"","size":1}},{"name":"g","type":"F"}]}`,
+			names:       []string{"F", "x.R"},
+			fields:      map[string]string{"f": "F", "g": "F"},
+		},
+		{
+			description: "types nested in null namespace type use null namespace",
+			schema:      `{"type":"record","name":"R","namespace":"x","fields":[{"name":"f","type":{"type":"record","name":"S","namespace":"","fields":[{"name":"g","type":{"type":"fixed","name":"F","size":1}}]}}]}`,
+			names:       []string{"F", "S", "x.R"},
+			fields:      map[string]string{"f": "S"},
+		},
+		{
+			description: "unqualified reference prefers enclosing namespace over null namespace",
+			schema:      `{"type":"record","name":"R","namespace":"x","fields":[{"name":"a","type":{"type":"fixed","name":"T","namespace":"","size":1}},{"name":"b","type":{"type":"fixed","name":"T","size":2}},{"name":"c","type":"T"}]}`,
+			names:       []string{"T", "x.R", "x.T"},
+			fields:      map[string]string{"a": "T", "b": "x.T", "c": "x.T"},
+		},
+		{
+			description: "qualified reference to type in other namespace",
+			schema:      `{"type":"record","name":"R","namespace":"x","fields":[{"name":"a","type":{"type":"fixed","name":"y.T","size":1}},{"name":"b","type":"y.T"}]}`,
+			names:       []string{"x.R", "y.T"},
+			fields:      map[string]string{"a": "y.T", "b": "y.T"},
+		},
+		{
+			description: "primitive names are not namespaced",
+			schema:      `{"type":"record","name":"R","namespace":"x","fields":[{"name":"int","type":"int"}]}`,
+			names:       []string{"x.R"},
+			fields:      map[string]string{"int": "int"},
+		},
+		{
+			description: "complex type names may be defined in a namespace",
+			schema:      `{"type":"record","name":"x.record","fields":[{"name":"f","type":"int"}]}`,
+			names:       []string{"x.record"},
+		},
+		{
+			description: "complex type keywords within schema objects are not named type references",
+			schema:      `{"type":"record","name":"x.R","fields":[{"name":"f","type":{"type":"fixed","name":"record","size":2}},{"name":"g","type":{"type":"record","name":"Inner","fields":[{"name":"z","type":"int"}]}},{"name":"h","type":"record"}]}`,
+			names:       []string{"x.Inner", "x.R", "x.record"},
+			fields:      map[string]string{"f": "x.record", "g": "x.Inner", "h": "x.record"},
+		},
+		{
+			description: "unqualified reference to type in other namespace",
+			schema:      `{"type":"record","name":"R","namespace":"x","fields":[{"name":"a","type":{"type":"fixed","name":"y.T","size":1}},{"name":"b","type":"T"}]}`,
+			err:         `unknown type name: "T"`,
+		},
+		{
+			description: "primitive type name in null namespace",
+			schema:      `{"type":"fixed","name":"int","size":1}`,
+			err:         "schema name ought to not redefine primitive type: int",
+		},
+		{
+			description: "primitive type name in namespace",
+			schema:      `{"type":"fixed","name":"string","namespace":"x","size":1}`,
+			err:         "schema name ought to not redefine primitive type: x.string",
+		},
+		{
+			description: "complex type name in null namespace",
+			schema:      `{"type":"record","name":"record","fields":[{"name":"f","type":"int"}]}`,
+			err:         "schema name ought to not redefine complex type: record",
+		},
+		{
+			description: "redefined type",
+			schema:      `{"type":"record","name":"R","fields":[{"name":"a","type":{"type":"fixed","name":"F","size":1}},{"name":"b","type":{"type":"fixed","name":"F","size":2}}]}`,
+			err:         `schema ought not redefine type: "F"`,
+		},
+		{
+			description: "field names have no namespace",
+			schema:      `{"type":"record","name":"R","fields":[{"name":"a.b","type":"int"}]}`,
+			err:         `Record "R" field 1 ought to have valid name: schema name ought to have second and remaining characters contain only [A-Za-z0-9_]: a.b`,
+		},
+		{
+			description: "namespace ought to be string",
+			schema:      `{"type":"fixed","name":"F","namespace":3,"size":1}`,
+			err:         "schema namespace, if provided, ought to be string",
+		},
+		{
+			description: "namespace components ought to be valid names",
+			schema:      `{"type":"fixed","name":"F","namespace":"a..b","size":1}`,
+			err:         "schema name ought to be non-empty string",
+		},
+	}
+
+	for _, c := range cases {
+		codec, err := NewCodec(c.schema)
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("%s: Actual: %v; Expected: %s", c.description, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", c.description, err)
+			continue
+		}
+		var names []string
+		for fullName := range codec.namedTypes {
+			names = append(names, fullName)
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, c.names) {
+			t.Errorf("%s: Actual: %v; Expected: %v", c.description, names, c.names)
+		}
+		for fieldName, expected := range c.fields {
+			field, ok := codec.node.(*RecordSchema).Field(fieldName)
+			if !ok {
+				t.Errorf("%s: field %q not found", c.description, fieldName)
+				continue
+			}
+			if actual := field.Schema().codec().typeName.fullName; actual != expected {
+				t.Errorf("%s: field %q: Actual: %v; Expected: %v", c.description, fieldName, actual, expected)
+			}
+		}
+	}
+}
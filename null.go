@@ -11,6 +11,8 @@ var nullBytes = []byte("null")
 
 func nullNativeFromBinary(buf []byte) (interface{}, []byte, error) { return nil, buf, nil }
 
+func nullSkipBinary(buf []byte) ([]byte, error) { return buf, nil }
+
 func nullBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 	if datum != nil {
 		return nil, fmt.Errorf("cannot encode binary null: expected: Go nil; received: %T", datum)
@@ -20,7 +22,7 @@ func nullBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 
 func nullNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 	if len(buf) < 4 {
-		return nil, nil, fmt.Errorf("cannot decode textual null: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual null: %w", io.ErrShortBuffer)
 	}
 	if bytes.Equal(buf[:4], nullBytes) {
 		return nil, buf[4:], nil
@@ -0,0 +1,54 @@
+package goavro
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// numericFromNative converts a Go numeric datum of a type the numeric encoders
+// do not handle directly, such as int16, uint32, json.Number, or a named type
+// like `type UserID int64`, to an int64 or a float64, which the encoders then
+// check for loss of precision. It returns a nil value and a nil error when
+// datum is not numeric, and an error when an unsigned value would overflow an
+// int64, or when a json.Number is not a valid number.
+func numericFromNative(datum interface{}) (interface{}, error) {
+	switch v := datum.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("provided Go json.Number is not a number: %q", v)
+		}
+		return f, nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	}
+
+	rv := reflect.ValueOf(datum)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return nil, fmt.Errorf("provided Go %T would overflow: %d", datum, u)
+		}
+		return int64(u), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+	return nil, nil
+}
@@ -48,6 +48,19 @@ const readBlockSize = 4096 // read and process data by blocks
 //    	return ocfr.Err()
 //    }
 func NewOCFReader(ior io.Reader) (*OCFReader, error) {
+	return NewOCFReaderWithOptions(ior)
+}
+
+// NewOCFReaderWithOptions initializes and returns a new structure used to read
+// an Avro Object Container File (OCF), whose Codec is created from the schema
+// of the file using the specified options. The WithMaxBlockCount and
+// WithMaxBlockSize options also limit the blocks of the file, and the limits
+// set by the WithMaxDecodedBytes, WithMaxDepth, WithMaxStringLength, and
+// WithMaxItems options apply to each datum read from it, which is useful when
+// reading files from untrusted sources.
+//
+//    ocfr, err := goavro.NewOCFReaderWithOptions(ior, goavro.WithMaxDepth(64), goavro.WithMaxDecodedBytes(1<<20))
+func NewOCFReaderWithOptions(ior io.Reader, options ...CodecOption) (*OCFReader, error) {
 	// NOTE: Wrap provided io.Reader in a buffered reader, which provides
 	// io.ByteReader interface, along with improving the performance of
 	// streaming file data.
@@ -93,7 +106,7 @@ func NewOCFReader(ior io.Reader) (*OCFReader, error) {
 	if !ok {
 		return nil, errors.New("cannot read without avro.schema")
 	}
-	bd, err := NewCodec(string(value))
+	bd, err := NewCodecWithOptions(string(value), options...)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create codec from invalid avro.schema: %s", err)
 	}
@@ -148,8 +161,10 @@ func (ocfr *OCFReader) Scan() bool {
 			ocfr.err = fmt.Errorf("cannot decode when block count is not greater than 0: %d", ocfr.remainingItems)
 			return false
 		}
-		if ocfr.remainingItems > MaxBlockCount {
-			ocfr.err = fmt.Errorf("cannot decode when block count exceeds MaxBlockCount: %d > %d", ocfr.remainingItems, MaxBlockCount)
+		maxBlockCount, maxBlockSize := ocfr.c.config.blockCountLimit(), ocfr.c.config.blockSizeLimit()
+		if ocfr.remainingItems > maxBlockCount {
+			ocfr.err = fmt.Errorf("cannot decode when block count exceeds MaxBlockCount: %d > %d", ocfr.remainingItems, maxBlockCount)
+			return false
 		}
 
 		var blockSize int64
@@ -162,8 +177,8 @@ func (ocfr *OCFReader) Scan() bool {
 			ocfr.err = fmt.Errorf("cannot decode when block size is not greater than 0: %d", blockSize)
 			return false
 		}
-		if blockSize > MaxBlockSize {
-			ocfr.err = fmt.Errorf("cannot decode when block size exceeds MaxBlockSize: %d > %d", blockSize, MaxBlockSize)
+		if blockSize > maxBlockSize {
+			ocfr.err = fmt.Errorf("cannot decode when block size exceeds MaxBlockSize: %d > %d", blockSize, maxBlockSize)
 			return false
 		}
 
@@ -183,11 +198,18 @@ func (ocfr *OCFReader) Scan() bool {
 			// NOTE: flate.NewReader wraps with io.ByteReader if argument does
 			// not implement that interface.
 			rc := flate.NewReader(bytes.NewBuffer(ocfr.block))
-			ocfr.block, ocfr.err = ioutil.ReadAll(rc)
+			// NOTE: Read one byte beyond the limit to detect when the
+			// decompressed block exceeds it.
+			ocfr.block, ocfr.err = ioutil.ReadAll(io.LimitReader(rc, maxBlockSize+1))
 			if ocfr.err != nil {
 				_ = rc.Close()
 				return false
 			}
+			if int64(len(ocfr.block)) > maxBlockSize {
+				_ = rc.Close()
+				ocfr.err = fmt.Errorf("cannot decompress when block size exceeds MaxBlockSize: > %d", maxBlockSize)
+				return false
+			}
 			if ocfr.err = rc.Close(); ocfr.err != nil {
 				return false
 			}
@@ -198,6 +220,15 @@ func (ocfr *OCFReader) Scan() bool {
 				ocfr.err = fmt.Errorf("cannot decompress snappy without CRC32 checksum: %d", len(ocfr.block))
 				return false
 			}
+			decodedSize, err := snappy.DecodedLen(ocfr.block[:index])
+			if err != nil {
+				ocfr.err = fmt.Errorf("cannot decompress: %s", err)
+				return false
+			}
+			if int64(decodedSize) > maxBlockSize {
+				ocfr.err = fmt.Errorf("cannot decompress when block size exceeds MaxBlockSize: %d > %d", decodedSize, maxBlockSize)
+				return false
+			}
 			decoded, err := snappy.Decode(nil, ocfr.block[:index])
 			if err != nil {
 				ocfr.err = fmt.Errorf("cannot decompress: %s", err)
@@ -366,7 +397,7 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 
 	// block count and block size
 	if value, err = longBinaryReader(ior); err != nil {
-		return nil, fmt.Errorf("cannot decode binary map block count: %s", err)
+		return nil, fmt.Errorf("cannot decode binary map block count: %w", err)
 	}
 	blockCount := value.(int64)
 	if blockCount < 0 {
@@ -380,7 +411,7 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 		// size in this decoder, so we read and discard the value.
 		blockCount = -blockCount // convert to its positive equivalent
 		if _, err = longBinaryReader(ior); err != nil {
-			return nil, fmt.Errorf("cannot decode binary map block size: %s", err)
+			return nil, fmt.Errorf("cannot decode binary map block size: %w", err)
 		}
 	}
 	// Ensure block count does not exceed some sane value.
@@ -399,7 +430,7 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 			// first decode the key string
 			keyBytes, err := bytesBinaryReader(ior)
 			if err != nil {
-				return nil, fmt.Errorf("cannot decode binary map key: %s", err)
+				return nil, fmt.Errorf("cannot decode binary map key: %w", err)
 			}
 			key := string(keyBytes)
 			if _, ok := mapValues[key]; ok {
@@ -414,7 +445,7 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 		}
 		// Decode next blockCount from buffer, because there may be more blocks
 		if value, err = longBinaryReader(ior); err != nil {
-			return nil, fmt.Errorf("cannot decode map block count: %s", err)
+			return nil, fmt.Errorf("cannot decode map block count: %w", err)
 		}
 		blockCount = value.(int64)
 		if blockCount < 0 {
@@ -428,7 +459,7 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 			// the block size in this decoder, so we read and discard the value.
 			blockCount = -blockCount // convert to its positive equivalent
 			if _, err = longBinaryReader(ior); err != nil {
-				return nil, fmt.Errorf("cannot decode map block size: %s", err)
+				return nil, fmt.Errorf("cannot decode map block size: %w", err)
 			}
 		}
 		// Ensure block count does not exceed some sane value.
@@ -2,6 +2,7 @@ package goavro_test
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/karrick/goavro"
@@ -18,3 +19,83 @@ func TestOCFReaderInvalidMagicBytes(t *testing.T) {
 	_, err := goavro.NewOCFReader(bb)
 	ensureError(t, err, "invalid magic bytes")
 }
+
+// ocfWithItems returns a null compressed OCF with one block holding the
+// specified binary encoded items of schema.
+func ocfWithItems(schema string, items ...[]byte) []byte {
+	sync := bytes.Repeat([]byte{0xAA}, 16)
+	buf := []byte("Obj\x01")
+	buf = append(buf, 0x2, byte(len("avro.schema")<<1))
+	buf = append(buf, "avro.schema"...)
+	buf = append(buf, byte(len(schema)<<1))
+	buf = append(buf, schema...)
+	buf = append(buf, 0)
+	buf = append(buf, sync...)
+
+	var block []byte
+	for _, item := range items {
+		block = append(block, item...)
+	}
+	buf = append(buf, byte(len(items)<<1), byte(len(block)<<1))
+	buf = append(buf, block...)
+	return append(buf, sync...)
+}
+
+func TestOCFReaderWithOptions(t *testing.T) {
+	ocf := ocfWithItems(`"string"`, []byte{0x2, 'a'}, []byte{0x6, 'a', 'b', 'c'})
+
+	ocfr, err := goavro.NewOCFReader(bytes.NewReader(ocf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	for ocfr.Scan() {
+		if _, err = ocfr.Read(); err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	if err = ocfr.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := count, 2; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	ocfr, err = goavro.NewOCFReaderWithOptions(bytes.NewReader(ocf), goavro.WithMaxStringLength(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ocfr.Scan() {
+		t.Fatal(ocfr.Err())
+	}
+	if _, err = ocfr.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if !ocfr.Scan() {
+		t.Fatal(ocfr.Err())
+	}
+	_, err = ocfr.Read()
+	ensureError(t, err, "exceeds maximum length: 3 > 2")
+	if !errors.Is(err, goavro.ErrMaxStringLength) {
+		t.Errorf("Actual: %v; Expected: %v", err, goavro.ErrMaxStringLength)
+	}
+
+	ocfr, err = goavro.NewOCFReaderWithOptions(bytes.NewReader(ocf), goavro.WithMaxBlockCount(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ocfr.Scan() {
+		t.Errorf("Actual: %v; Expected: %v", true, false)
+	}
+	ensureError(t, ocfr.Err(), "block count exceeds MaxBlockCount: 2 > 1")
+
+	ocfr, err = goavro.NewOCFReaderWithOptions(bytes.NewReader(ocf), goavro.WithMaxBlockSize(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ocfr.Scan() {
+		t.Errorf("Actual: %v; Expected: %v", true, false)
+	}
+	ensureError(t, ocfr.Err(), "block size exceeds MaxBlockSize: 6 > 5")
+}
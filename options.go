@@ -0,0 +1,278 @@
+package goavro
+
+// CodecOption configures a Codec created by NewCodecWithOptions. Options only
+// affect the Codec they are provided to, so Codecs using different options may
+// be used simultaneously in a program.
+type CodecOption func(*codecConfig)
+
+// codecConfig holds the configuration collected from the options provided to
+// NewCodecWithOptions, and is consulted while building each codec of a schema,
+// and by the codecs themselves.
+type codecConfig struct {
+	schemaHooks    []SchemaHook
+	strictFields   bool
+	logicalTypes   bool
+	maxBlockCount  int64 // when zero, MaxBlockCount applies
+	maxBlockSize   int64 // when zero, MaxBlockSize applies
+	registry       *TypeRegistry
+	zeroCopy       bool // strings decoded from binary refer to the input
+	copyBytes      bool // bytes and fixed decoded from binary are copied
+	unionInference bool // union encoders accept values not wrapped in a map
+	sortedMapKeys  bool // map entries are encoded in sorted key order
+	enumDefaults   bool // unknown enum indexes and symbols decode as the default
+
+	// when positive, array and map encoders write blocks of this many items
+	// preceded by their byte size
+	sizedBlockCount int64
+
+	// representation of decoded union values
+	bareNullableUnions bool
+	unionValues        bool
+
+	// limits of decoded binary data, which are not enforced when zero
+	maxDecodedBytes int64
+	maxDepth        int
+	maxStringLength int64
+	maxItems        int64
+}
+
+// SchemaHook is a function invoked while a Codec is being built, once for each
+// named type and once for each record field of its schema, after the node
+// describing it is complete. A hook may inspect the documentation string and
+// the custom properties of the node, and may stop the Codec from being created
+// by returning an error.
+type SchemaHook func(node Annotated) error
+
+// WithSchemaHook returns an option that invokes hook for every named type and
+// record field of the schema while the Codec is being built. When more than
+// one hook is provided, they are invoked in the order provided.
+func WithSchemaHook(hook SchemaHook) CodecOption {
+	return func(config *codecConfig) {
+		config.schemaHooks = append(config.schemaHooks, hook)
+	}
+}
+
+// WithStrictFields returns an option that causes the binary and textual
+// encoders of every record of the schema to return an error naming each key of
+// the provided map[string]interface{} that is not a field of the record, rather
+// than ignoring it. Codec.Validate also reports those keys.
+func WithStrictFields() CodecOption {
+	return func(config *codecConfig) {
+		config.strictFields = true
+	}
+}
+
+// WithMaxBlockCount returns an option that limits the number of items the
+// Codec accepts in a single array or map block while decoding, and the number
+// of items it writes in a single block while encoding, in place of the package
+// variable MaxBlockCount.
+func WithMaxBlockCount(count int64) CodecOption {
+	return func(config *codecConfig) {
+		config.maxBlockCount = count
+	}
+}
+
+// WithMaxBlockSize returns an option that limits the byte size of array and
+// map blocks the Codec accepts while decoding, when the encoder provided the
+// block size, in place of the package variable MaxBlockSize.
+func WithMaxBlockSize(size int64) CodecOption {
+	return func(config *codecConfig) {
+		config.maxBlockSize = size
+	}
+}
+
+// WithSizedBlocks returns an option that causes the binary encoders of arrays
+// and maps to write blocks of at most count items, each preceded by its item
+// count as a negative number, followed by its byte size, as the Avro
+// specification permits. Readers, including projection codecs, may then skip
+// over entire blocks without decoding their items. The limit set by
+// WithMaxBlockCount or MaxBlockCount continues to apply when lower than count.
+func WithSizedBlocks(count int64) CodecOption {
+	return func(config *codecConfig) {
+		config.sizedBlockCount = count
+	}
+}
+
+// WithLogicalTypes returns an option that causes the Codec to translate values
+// of the following logical types to and from Go time types:
+//
+//     date              int   time.Time, at midnight UTC
+//     time-millis       int   time.Duration since midnight, less than 24h
+//     time-micros       long  time.Duration since midnight, less than 24h
+//     timestamp-millis  long  time.Time, in UTC
+//     timestamp-micros  long  time.Time, in UTC
+//
+// The encoders of those types continue to accept numeric values. As required
+// by the Avro specification, other logical types, and logical types annotating
+// a different underlying type, are ignored.
+func WithLogicalTypes() CodecOption {
+	return func(config *codecConfig) {
+		config.logicalTypes = true
+	}
+}
+
+// WithRegistry returns an option that resolves references to named types that
+// the schema does not define using the types registered with registry.
+func WithRegistry(registry *TypeRegistry) CodecOption {
+	return func(config *codecConfig) {
+		config.registry = registry
+	}
+}
+
+// WithZeroCopy returns an option that causes the binary decoders of strings,
+// including map keys, to return strings that refer to the bytes of the input
+// buffer rather than to copies of them, which saves an allocation and a copy
+// for each string. Binary decoders of bytes always return slices of the input
+// buffer, unless the WithCopyBytes option is provided.
+//
+// This option is only safe when the input buffer is never modified after it is
+// decoded, for as long as any decoded string is in use, because Go strings are
+// assumed to be immutable. Modifying the buffer changes the strings decoded
+// from it, including keys of decoded maps.
+func WithZeroCopy() CodecOption {
+	return func(config *codecConfig) {
+		config.zeroCopy = true
+	}
+}
+
+// WithCopyBytes returns an option that causes the binary decoders of bytes and
+// fixed values to return copies of the bytes of the input buffer rather than
+// slices of it, so the caller may reuse the buffer as soon as the datum is
+// decoded.
+func WithCopyBytes() CodecOption {
+	return func(config *codecConfig) {
+		config.copyBytes = true
+	}
+}
+
+// WithUnionInference returns an option that causes the binary and textual
+// encoders of unions to accept values that are not wrapped in a map naming the
+// member type, such as "some string" rather than goavro.Union("string", "some
+// string"), and to encode them using the member inferred from their Go type:
+//
+//     bool                                 boolean
+//     string                               string, or else the only enum listing it
+//     []byte                               bytes, or else the only fixed of its size
+//     int8, int16, int32, uint8, uint16    int, long, double, float
+//     int, int64, uint, uint32, uint64     long, int, double, float
+//     float32                              float, double, long, int
+//     float64                              double, float, long, int
+//     json.Number                          as int64 when integral, else as float64
+//     time.Time                            the only date or timestamp member
+//     other slices                         array
+//     map[string]interface{}               the only map or record it is valid for
+//     other maps                           map
+//
+// Numeric values, including values of named numeric types, use the first
+// member of their list that the union has and that encodes the value without
+// losing precision, so an int64 too large for an int uses a long or double
+// member instead. Date and timestamp members only translate time.Time values
+// when the WithLogicalTypes option is also used. The encoders return an error
+// when no member supports a value, or when more than one enum, fixed, map,
+// record, date, or timestamp member supports it. A map with a single key naming a member continues
+// to be treated as a wrapped value.
+func WithUnionInference() CodecOption {
+	return func(config *codecConfig) {
+		config.unionInference = true
+	}
+}
+
+// WithEnumDefaults returns an option that causes the binary and textual
+// decoders of enums that specify a default symbol to return the default symbol
+// when they read an index or a symbol the enum does not have, as the Avro
+// specification requires of readers of data written using a newer schema.
+// Without this option, and for enums without a default symbol, the decoders
+// return an error. Encoders are not affected.
+func WithEnumDefaults() CodecOption {
+	return func(config *codecConfig) {
+		config.enumDefaults = true
+	}
+}
+
+// WithBareNullableUnions returns an option that causes the decoders of unions
+// of null and one other type, such as ["null","string"], to return non-nil
+// values as they are, rather than wrapped in a map naming their type. The
+// encoders of those unions accept such values as well. When the other type is a
+// record or a map, a map datum is always taken as its bare value, even when its
+// single key names the type, so a wrapped value ought to be a UnionValue. Other
+// unions are not affected.
+func WithBareNullableUnions() CodecOption {
+	return func(config *codecConfig) {
+		config.bareNullableUnions = true
+	}
+}
+
+// WithUnionValues returns an option that causes the decoders of unions to
+// return non-nil values as a UnionValue, which holds the index and the name of
+// the decoded member along with its value, rather than wrapped in a map naming
+// their type. When combined with the WithBareNullableUnions option, unions of
+// null and one other type return bare values instead.
+func WithUnionValues() CodecOption {
+	return func(config *codecConfig) {
+		config.unionValues = true
+	}
+}
+
+// WithSortedMapKeys returns an option that causes the binary and textual
+// encoders of maps to write their entries in sorted key order, and the textual
+// encoders of records to write their fields in sorted name order, rather than
+// in the random order in which Go iterates over maps. Because the binary
+// encoders of records always write fields in schema order, encoding the same
+// datum always produces the same bytes, so the output may be hashed or used as
+// a content-addressable key.
+func WithSortedMapKeys() CodecOption {
+	return func(config *codecConfig) {
+		config.sortedMapKeys = true
+	}
+}
+
+// stringFromBinary returns the function used to decode binary strings,
+// including map keys.
+func (config *codecConfig) stringFromBinary() func([]byte) (string, []byte, error) {
+	if config.zeroCopy {
+		return stringFromBinaryZeroCopy
+	}
+	return stringFromBinary
+}
+
+// blockCountLimit returns the maximum number of items of an array or map
+// block.
+func (config *codecConfig) blockCountLimit() int64 {
+	if config.maxBlockCount > 0 {
+		return config.maxBlockCount
+	}
+	return MaxBlockCount
+}
+
+// streamBlockCount returns the maximum number of items of an array or map
+// block encoded from a producer, which also bounds the number of encoded items
+// held in memory when writing to an io.Writer.
+func (config *codecConfig) streamBlockCount() int64 {
+	count := config.blockCountLimit()
+	if config.sizedBlockCount > 0 && config.sizedBlockCount < count {
+		return config.sizedBlockCount
+	}
+	if config.maxBlockCount == 0 && count > defaultStreamBlockCount {
+		return defaultStreamBlockCount
+	}
+	return count
+}
+
+// blockSizeLimit returns the maximum byte size of an array or map block.
+func (config *codecConfig) blockSizeLimit() int64 {
+	if config.maxBlockSize > 0 {
+		return config.maxBlockSize
+	}
+	return MaxBlockSize
+}
+
+// runSchemaHooks invokes each of the configured schema hooks with node, and
+// returns the first error returned by a hook.
+func (st *symbolTable) runSchemaHooks(node Annotated) error {
+	for _, hook := range st.config.schemaHooks {
+		if err := hook(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package goavro_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestSchemaHookVisitsNamedTypesAndFields(t *testing.T) {
+	var visited []string
+	hook := func(node goavro.Annotated) error {
+		switch n := node.(type) {
+		case *goavro.FieldSchema:
+			visited = append(visited, fmt.Sprintf("field %s.%s", n.Record().Name(), n.Name()))
+		case *goavro.RecordSchema:
+			visited = append(visited, "record "+n.Name())
+		case *goavro.EnumSchema:
+			visited = append(visited, "enum "+n.Name())
+		case *goavro.FixedSchema:
+			visited = append(visited, "fixed "+n.Name())
+		}
+		return nil
+	}
+	_, err := goavro.NewCodecWithOptions(`
+{"type": "record", "name": "r1",
+ "fields": [
+     {"name": "f1", "type": {"type": "enum", "name": "e1", "symbols": ["alpha"]}},
+     {"name": "f2", "type": {"type": "fixed", "name": "x1", "size": 4}}
+ ]
+}`, goavro.WithSchemaHook(hook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", visited), "[enum e1 field r1.f1 fixed x1 field r1.f2 record r1]"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
+
+func TestSchemaHookMayRejectSchema(t *testing.T) {
+	hook := func(node goavro.Annotated) error {
+		if field, ok := node.(*goavro.FieldSchema); ok {
+			if pii, _ := field.Property("pii"); pii == true && field.Doc() == "" {
+				return errors.New("PII fields ought to be documented")
+			}
+		}
+		return nil
+	}
+	_, err := goavro.NewCodecWithOptions(`{"type":"record","name":"r1","fields":[{"name":"email","type":"string","pii":true}]}`, goavro.WithSchemaHook(hook))
+	ensureError(t, err, `Record "r1" field "email": PII fields ought to be documented`)
+
+	_, err = goavro.NewCodecWithOptions(`{"type":"record","name":"r1","fields":[{"name":"email","type":"string","pii":true,"doc":"contact"}]}`, goavro.WithSchemaHook(hook))
+	if err != nil {
+		t.Errorf("Actual: %v; Expected: %v", err, nil)
+	}
+}
+
+func TestWithStrictFields(t *testing.T) {
+	schema := `{"type":"record","name":"User","fields":[{"name":"userId","type":"long"},{"name":"address","type":{"type":"record","name":"Address","fields":[{"name":"city","type":"string","default":""}]}}]}`
+	datum := map[string]interface{}{
+		"userId":  int64(1),
+		"address": map[string]interface{}{"city": "Springfield", "zip": "12345", "country": "US"},
+	}
+
+	// By default, unknown keys are ignored
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = codec.BinaryFromNative(nil, datum); err != nil {
+		t.Fatal(err)
+	}
+
+	codec, err = goavro.NewCodecWithOptions(schema, goavro.WithStrictFields())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.BinaryFromNative(nil, datum)
+	ensureError(t, err, `cannot encode binary record "Address": unknown fields: ["country" "zip"]`)
+	_, err = codec.TextualFromNative(nil, datum)
+	ensureError(t, err, `cannot encode textual record "Address": unknown fields: ["country" "zip"]`)
+	_, err = codec.BinaryFromNative(nil, map[string]interface{}{"usrId": int64(1), "address": map[string]interface{}{}})
+	ensureError(t, err, `unknown fields: ["usrId"]`)
+
+	violations := codec.Validate(datum)
+	var paths []string
+	for _, violation := range violations {
+		paths = append(paths, violation.Path)
+	}
+	if actual, expected := fmt.Sprint(paths), "[/address/country /address/zip]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	delete(datum["address"].(map[string]interface{}), "zip")
+	delete(datum["address"].(map[string]interface{}), "country")
+	if _, err = codec.BinaryFromNative(nil, datum); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithMaxBlockCount(t *testing.T) {
+	schema := `{"type":"array","items":"int"}`
+	codec, err := goavro.NewCodecWithOptions(schema, goavro.WithMaxBlockCount(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// encoder writes no more than 2 items per block
+	buf, err := codec.BinaryFromNative(nil, []interface{}{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := buf, []byte{0x4, 0x2, 0x4, 0x2, 0x6, 0}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	_, _, err = codec.NativeFromBinary([]byte{0x6, 0x2, 0x4, 0x6, 0})
+	ensureError(t, err, "block count exceeds MaxBlockCount: 3 > 2")
+
+	// other codecs are not affected
+	other, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = other.NativeFromBinary([]byte{0x6, 0x2, 0x4, 0x6, 0}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithMaxBlockSize(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"map","values":"int"}`, goavro.WithMaxBlockSize(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = codec.NativeFromBinary([]byte{0x1, 0x6, 0x2, 'k', 0x6, 0})
+	ensureError(t, err, "block size exceeds MaxBlockSize: 3 > 2")
+}
@@ -0,0 +1,189 @@
+package goavro
+
+import (
+	"errors"
+	"fmt"
+)
+
+// projection describes the fields selected within a record. Each key is the
+// name of a selected field, and its value describes the fields selected within
+// that field, or is nil when the entire field value is selected.
+type projection map[string]projection
+
+// NewProjectionCodec returns a Codec that decodes binary data encoded using
+// writerSchema, but only materializes the record fields selected by fieldPaths.
+// The bytes of other fields are skipped without being decoded, and arrays and
+// maps written with block sizes are skipped one block at a time. Each field
+// path selects a field using the same syntax as MaskRule.Path, for instance
+// "/order/customer/email", and selecting a field selects its entire value.
+// Decoded records only contain the selected fields.
+//
+// The returned Codec only decodes binary data: its other methods return an
+// error, while its Schema and SchemaTree methods describe writerSchema.
+//
+//     codec, err := goavro.NewProjectionCodec(schema, []string{"/id", "/customer/email"})
+//     if err != nil {
+//             fmt.Println(err)
+//     }
+//     datum, _, err := codec.NativeFromBinary(binary)
+func NewProjectionCodec(writerSchema string, fieldPaths []string, options ...CodecOption) (*Codec, error) {
+	if len(fieldPaths) == 0 {
+		return nil, errors.New("cannot create projection codec: ought to have one or more field paths")
+	}
+	writer, err := NewCodecWithOptions(writerSchema, options...)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(projection)
+	for _, path := range fieldPaths {
+		if _, err = resolveFieldPath(writer.node, path); err != nil {
+			return nil, fmt.Errorf("cannot create projection codec: %s", err)
+		}
+		names, _ := splitFieldPath(path) // already validated by resolveFieldPath
+		selected.add(names)
+	}
+	projected := project(writer.node, selected)
+
+	return &Codec{
+		typeName:         writer.typeName,
+		schema:           writer.schema,
+		node:             writer.node,
+		namedTypes:       writer.namedTypes,
+		config:           writer.config,
+		nativeFromBinary: projected.nativeFromBinary,
+		skipBinary:       writer.skipBinary,
+		binaryFromNative: func(_ []byte, _ interface{}) ([]byte, error) {
+			return nil, errors.New("cannot encode binary using projection codec")
+		},
+		nativeFromTextual: func(_ []byte) (interface{}, []byte, error) {
+			return nil, nil, errors.New("cannot decode textual using projection codec")
+		},
+		textualFromNative: func(_ []byte, _ interface{}) ([]byte, error) {
+			return nil, errors.New("cannot encode textual using projection codec")
+		},
+	}, nil
+}
+
+// add selects the field at the path described by names.
+func (p projection) add(names []string) {
+	nested, ok := p[names[0]]
+	if ok && nested == nil {
+		return // entire field already selected
+	}
+	if len(names) == 1 {
+		p[names[0]] = nil
+		return
+	}
+	if nested == nil {
+		nested = make(projection)
+		p[names[0]] = nested
+	}
+	nested.add(names[1:])
+}
+
+// project returns a codec that decodes values described by s, only
+// materializing the fields selected within the records reachable from s.
+func project(s Schema, selected projection) *Codec {
+	switch v := s.(type) {
+	case *RecordSchema:
+		return projectRecord(v, selected)
+	case *ArraySchema:
+		if len(recordsWithin(v.items)) > 0 {
+			return newArrayCodec(project(v.items, selected), v.c.config)
+		}
+	case *MapSchema:
+		if len(recordsWithin(v.values)) > 0 {
+			return newMapCodec(project(v.values, selected), v.c.config)
+		}
+	case *UnionSchema:
+		if len(recordsWithin(v)) > 0 {
+			return projectUnion(v, selected)
+		}
+	}
+	return s.codec()
+}
+
+// projectRecord returns a codec that decodes a record, materializing only the
+// selected fields, and skipping the others.
+func projectRecord(r *RecordSchema, selected projection) *Codec {
+	writer := r.codec()
+	names := make([]string, len(r.fields))
+	codecFromIndex := make([]*Codec, len(r.fields)) // nil when field is skipped
+	for i, field := range r.fields {
+		names[i] = field.name
+		nested, ok := selected[field.name]
+		if !ok {
+			continue
+		}
+		if nested == nil {
+			codecFromIndex[i] = field.schema.codec()
+		} else {
+			codecFromIndex[i] = project(field.schema, nested)
+		}
+	}
+
+	c := &Codec{
+		typeName:   writer.typeName,
+		schema:     writer.schema,
+		node:       r,
+		skipBinary: writer.skipBinary,
+	}
+	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
+		recordMap := make(map[string]interface{}, len(selected))
+		for i, fieldCodec := range codecFromIndex {
+			var err error
+			if fieldCodec == nil {
+				if buf, err = r.fields[i].schema.codec().skipBinary(buf); err != nil {
+					return nil, nil, asDecodeError(err, r.fields[i].schema.codec()).within(names[i]).prefixed("cannot decode binary record %q field %q", c.typeName, names[i])
+				}
+				continue
+			}
+			var value interface{}
+			if value, buf, err = fieldCodec.nativeFromBinary(buf); err != nil {
+				return nil, nil, asDecodeError(err, fieldCodec).within(names[i]).prefixed("cannot decode binary record %q field %q", c.typeName, names[i])
+			}
+			recordMap[names[i]] = value
+		}
+		return recordMap, buf, nil
+	}
+	return c
+}
+
+// projectUnion returns a codec that decodes a union whose members are
+// projected using the selected fields.
+func projectUnion(u *UnionSchema, selected projection) *Codec {
+	codecFromIndex := make([]*Codec, len(u.members))
+	allowedTypes := make([]string, len(u.members))
+	for i, member := range u.members {
+		codecFromIndex[i] = project(member, selected)
+		allowedTypes[i] = member.codec().typeName.fullName
+	}
+
+	writer := u.codec()
+	representation := unionRepresentation(writer.config, allowedTypes)
+	return &Codec{
+		typeName:   writer.typeName,
+		schema:     writer.schema,
+		node:       u,
+		skipBinary: writer.skipBinary,
+		nativeFromBinary: func(buf []byte) (interface{}, []byte, error) {
+			var decoded interface{}
+			index, buf, err := longFromBinary(buf)
+			if err != nil {
+				return nil, nil, err
+			}
+			if index < 0 || index >= int64(len(codecFromIndex)) {
+				return nil, nil, fmt.Errorf("cannot decode binary union: index ought to be between 0 and %d; read index: %d", len(codecFromIndex)-1, index)
+			}
+			decoded, buf, err = codecFromIndex[index].nativeFromBinary(buf)
+			if err != nil {
+				return nil, nil, asDecodeError(err, codecFromIndex[index]).within(allowedTypes[index]).prefixed("cannot decode binary union item %d", index+1)
+			}
+			if decoded == nil {
+				// do not wrap a nil value in a map
+				return nil, buf, nil
+			}
+			return wrapUnionValue(representation, allowedTypes, int(index), decoded), buf, nil
+		},
+	}
+}
@@ -0,0 +1,138 @@
+package goavro_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestProjectionCodecSelectsFields(t *testing.T) {
+	codec, err := goavro.NewCodec(maskTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, newMaskTestDatum())
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf = append(buf, 0xFF) // trailing byte ought to remain
+
+	projection, err := goavro.NewProjectionCodec(maskTestSchema, []string{"/id", "/customer/phone", "/notes/text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum, remaining, err := projection.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := remaining, []byte{0xFF}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	expected := map[string]interface{}{
+		"id":       int64(42),
+		"customer": map[string]interface{}{"phone": goavro.Union("string", "555-1212")},
+		"notes": []interface{}{
+			map[string]interface{}{"text": "first"},
+			map[string]interface{}{"text": "second"},
+		},
+	}
+	if !reflect.DeepEqual(datum, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", datum, expected)
+	}
+}
+
+func TestProjectionCodecWholeField(t *testing.T) {
+	codec, err := goavro.NewCodec(maskTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, newMaskTestDatum())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// selecting a field and a field within it selects the entire field
+	projection, err := goavro.NewProjectionCodec(maskTestSchema, []string{"/customer/email", "/customer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum, _, err := projection.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"customer": newMaskTestDatum()["customer"]}
+	if !reflect.DeepEqual(datum, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", datum, expected)
+	}
+}
+
+func TestProjectionCodecSkipsBlockSize(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[{"name":"a","type":{"type":"array","items":"long"}},{"name":"m","type":{"type":"map","values":"int"}},{"name":"b","type":"string"}]}`
+	projection, err := goavro.NewProjectionCodec(schema, []string{"/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := []byte{
+		0x3, 0x4, 0x2, 0x4, 0, // array block with count -2 and size 2, then end of array
+		0x1, 0x6, 0x2, 'k', 0x6, 0, // map block with count -1 and size 3, then end of map
+		0x6, 'f', 'o', 'o', // string
+	}
+	datum, remaining, err := projection.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("Actual: %#v; Expected: %#v", remaining, []byte{})
+	}
+	if expected := map[string]interface{}{"b": "foo"}; !reflect.DeepEqual(datum, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", datum, expected)
+	}
+
+	_, _, err = projection.NativeFromBinary([]byte{0x3, 0x8, 0x2, 0x4, 0})
+	ensureError(t, err, "cannot skip binary array block size")
+}
+
+func TestProjectionCodecRecursive(t *testing.T) {
+	schema := `{"type":"record","name":"LongList","fields":[{"name":"value","type":"long"},{"name":"next","type":["null","LongList"]}]}`
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, map[string]interface{}{
+		"value": int64(1),
+		"next":  goavro.Union("LongList", map[string]interface{}{"value": int64(2), "next": nil}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projection, err := goavro.NewProjectionCodec(schema, []string{"/next/value"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum, _, err := projection.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"next": goavro.Union("LongList", map[string]interface{}{"value": int64(2)})}
+	if !reflect.DeepEqual(datum, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", datum, expected)
+	}
+}
+
+func TestProjectionCodecErrors(t *testing.T) {
+	_, err := goavro.NewProjectionCodec(maskTestSchema, nil)
+	ensureError(t, err, "ought to have one or more field paths")
+
+	_, err = goavro.NewProjectionCodec(maskTestSchema, []string{"/customer/missing"})
+	ensureError(t, err, "cannot find field path")
+
+	projection, err := goavro.NewProjectionCodec(maskTestSchema, []string{"/id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = projection.BinaryFromNative(nil, map[string]interface{}{"id": int64(1)})
+	ensureError(t, err, "cannot encode binary using projection codec")
+
+	_, _, err = projection.NativeFromBinary([]byte{0x54, 0x80})
+	ensureError(t, err, "cannot decode binary record \"Order\" field \"customer\"")
+}
@@ -2,9 +2,10 @@ package goavro
 
 import (
 	"fmt"
+	"sort"
 )
 
-func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
+func makeRecordCodec(st *symbolTable, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
 	// NOTE: To support recursive data types, create the codec and register it
 	// using the specified name, and fill in the codec functions later.
 	c, err := registerNewCodec(st, schemaMap, enclosingNamespace)
@@ -21,10 +22,18 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 		return nil, fmt.Errorf("Record %q fields ought to be non-empty array: %v", c.typeName, fields)
 	}
 
+	doc, aliases := attributesFromSchemaMap(schemaMap)
+	// NOTE: Set the schema node before building the field codecs, so fields
+	// that recursively refer to this record share its node.
+	props := propertiesFromSchemaMap(schemaMap, "aliases", "doc", "fields", "name", "namespace", "type")
+	recordSchema := &RecordSchema{namedSchema: namedSchema{properties: props, c: c, aliases: aliases, doc: doc}}
+	c.node = recordSchema
+
 	codecFromFieldName := make(map[string]*Codec)
 	codecFromIndex := make([]*Codec, len(fieldSchemas))
 	nameFromIndex := make([]string, len(fieldSchemas))
 	defaultValueFromName := make(map[string]interface{}, len(fieldSchemas))
+	defaultBinaryFromName := make(map[string][]byte, len(fieldSchemas))
 
 	for i, fieldSchema := range fieldSchemas {
 		fieldSchemaMap, ok := fieldSchema.(map[string]interface{})
@@ -35,86 +44,174 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 		// NOTE: field names are not registered in the symbol table, because
 		// field names are not individually addressable codecs.
 
-		fieldCodec, err := buildCodecForTypeDescribedByMap(st, c.typeName.namespace, fieldSchemaMap)
+		var fieldCodec *Codec
+		var err error
+		typeName, _ := fieldSchemaMap["type"].(string)
+		if _, ok = complexTypeNames[typeName]; ok {
+			// NOTE: A field object does not itself describe a complex type, so
+			// the name of one can only refer to a named type.
+			fieldCodec, err = buildCodecForTypeDescribedByString(st, c.typeName.namespace, typeName, nil)
+		} else {
+			fieldCodec, err = buildCodecForTypeDescribedByMap(st, c.typeName.namespace, fieldSchemaMap)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("Record %q field %d ought to be valid Avro named type: %s", c.typeName, i+1, err)
 		}
 
-		// However, when creating a full name for the field name, be sure to use
-		// record's namespace
-		n, err := newNameFromSchemaMap(c.typeName.namespace, fieldSchemaMap)
+		fieldName, err := fieldNameFromSchemaMap(fieldSchemaMap)
 		if err != nil {
-			return nil, fmt.Errorf("Record %q field %d ought to have valid name: %v", c.typeName, i+1, fieldSchemaMap)
+			return nil, fmt.Errorf("Record %q field %d ought to have valid name: %s", c.typeName, i+1, err)
 		}
-		fieldName := n.short()
 		if _, ok := codecFromFieldName[fieldName]; ok {
 			return nil, fmt.Errorf("Record %q field %d ought to have unique name: %q", c.typeName, i+1, fieldName)
 		}
 
+		order := OrderAscending
+		if o, ok := fieldSchemaMap["order"]; ok {
+			switch o {
+			case OrderAscending, OrderDescending, OrderIgnore:
+				order = o.(string)
+			default:
+				return nil, fmt.Errorf("Record %q field %q order ought to be one of %q, %q, or %q: %v", c.typeName, fieldName, OrderAscending, OrderDescending, OrderIgnore, o)
+			}
+		}
+		fieldDoc, fieldAliases := attributesFromSchemaMap(fieldSchemaMap)
+		fieldSchema := &FieldSchema{
+			properties: propertiesFromSchemaMap(fieldSchemaMap, "aliases", "default", "doc", "name", "order", "type"),
+			aliases:    fieldAliases,
+			doc:        fieldDoc,
+			name:       fieldName,
+			order:      order,
+			record:     recordSchema,
+			schema:     fieldCodec.node,
+		}
+
 		if defaultValue, ok := fieldSchemaMap["default"]; ok {
-			// if codec is union, then default value ought to encode using first schema in union
-			if fieldCodec.typeName.short() == "union" {
-				// NOTE: To support record field default values, union schema
-				// set to the type name of first member
-				defaultValue = Union(fieldCodec.schema, defaultValue)
-			}
-			// attempt to encode default value using codec
-			_, err = fieldCodec.binaryFromNative(nil, defaultValue)
+			// NOTE: Convert the default value to the native form of the field
+			// by encoding it and decoding the result, which yields values of
+			// the same Go types as decoding data does. The encoded default is
+			// kept, so each use decodes a fresh copy, and decoded values never
+			// share maps or slices.
+			encoded, defaultValue, err := binaryFromDefault(fieldCodec, defaultValue)
 			if err != nil {
-				return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %s", c.typeName, fieldName, err)
+				return nil, fmt.Errorf("Record %q field %q: %s", c.typeName, fieldName, err)
 			}
+			defaultBinaryFromName[fieldName] = encoded
 			defaultValueFromName[fieldName] = defaultValue
+			fieldSchema.defaultValue = defaultValue
+			fieldSchema.hasDefault = true
+		}
+		if err = st.runSchemaHooks(fieldSchema); err != nil {
+			return nil, fmt.Errorf("Record %q field %q: %s", c.typeName, fieldName, err)
 		}
+		recordSchema.fields = append(recordSchema.fields, fieldSchema)
 
 		nameFromIndex[i] = fieldName
 		codecFromIndex[i] = fieldCodec
 		codecFromFieldName[fieldName] = fieldCodec
 	}
+	if err = st.runSchemaHooks(recordSchema); err != nil {
+		return nil, fmt.Errorf("Record %q: %s", c.typeName, err)
+	}
 
-	c.binaryFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+	// appendFields appends the binary encoding of the record datum to buf,
+	// using appendField, when not nil, to encode the provided field values.
+	recordSchema.appendFields = func(buf []byte, datum interface{}, appendField func([]byte, *Codec, interface{}) ([]byte, error)) ([]byte, error) {
 		valueMap, ok := datum.(map[string]interface{})
 		if !ok {
 			return nil, fmt.Errorf("cannot encode binary record %q: expected map[string]interface{}; received: %T", c.typeName, datum)
 		}
+		if c.config.strictFields {
+			if unknown := unknownFieldNames(valueMap, codecFromFieldName); unknown != nil {
+				return nil, fmt.Errorf("cannot encode binary record %q: unknown fields: %q", c.typeName, unknown)
+			}
+		}
 
 		// records encoded in order fields were defined in schema
 		for i, fieldCodec := range codecFromIndex {
 			fieldName := nameFromIndex[i]
 
-			// NOTE: If field value was not specified in map, then set
-			// fieldValue to its default value (which may or may not have been
+			// NOTE: If field value was not specified in map, then append the
+			// encoded default value (which may or may not have been
 			// specified).
 			fieldValue, ok := valueMap[fieldName]
 			if !ok {
-				if fieldValue, ok = defaultValueFromName[fieldName]; !ok {
+				encoded, ok := defaultBinaryFromName[fieldName]
+				if !ok {
 					return nil, fmt.Errorf("cannot encode binary record %q field %q: schema does not specify default value and no value provided", c.typeName, fieldName)
 				}
+				buf = append(buf, encoded...)
+				continue
 			}
 
 			var err error
-			buf, err = fieldCodec.binaryFromNative(buf, fieldValue)
+			if appendField != nil {
+				buf, err = appendField(buf, fieldCodec, fieldValue)
+			} else {
+				buf, err = fieldCodec.binaryFromNative(buf, fieldValue)
+			}
 			if err != nil {
-				return nil, fmt.Errorf("cannot encode binary record %q field %q: value does not match its schema: %s", c.typeName, fieldName, err)
+				return nil, asEncodeError(err, fieldCodec, fieldValue).within(fieldName).prefixed("cannot encode binary record %q field %q: value does not match its schema", c.typeName, fieldName)
 			}
 		}
 		return buf, nil
 	}
 
-	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
-		recordMap := make(map[string]interface{}, len(codecFromIndex))
+	c.binaryFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+		return recordSchema.appendFields(buf, datum, nil)
+	}
+
+	c.binaryInto = func(buf []byte, dst interface{}) (interface{}, []byte, error) {
+		recordMap, ok := dst.(map[string]interface{})
+		if ok {
+			for key := range recordMap {
+				if _, ok := codecFromFieldName[key]; !ok {
+					delete(recordMap, key)
+				}
+			}
+		} else {
+			recordMap = make(map[string]interface{}, len(codecFromIndex))
+		}
 		for i, fieldCodec := range codecFromIndex {
 			name := nameFromIndex[i]
 			var value interface{}
 			var err error
-			value, buf, err = fieldCodec.nativeFromBinary(buf)
+			value, buf, err = fieldCodec.nativeFromBinaryInto(buf, recordMap[name])
 			if err != nil {
-				return nil, nil, fmt.Errorf("cannot decode binary record %q field %q: %s", c.typeName, name, err)
+				return nil, nil, asDecodeError(err, fieldCodec).within(name).prefixed("cannot decode binary record %q field %q", c.typeName, name)
 			}
 			recordMap[name] = value
 		}
 		return recordMap, buf, nil
 	}
 
+	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
+		// NOTE: Decoding into a new map does not look up the previous value of
+		// each field, as binaryInto does.
+		recordMap := make(map[string]interface{}, len(codecFromIndex))
+		for i, fieldCodec := range codecFromIndex {
+			var value interface{}
+			var err error
+			if value, buf, err = fieldCodec.nativeFromBinary(buf); err != nil {
+				name := nameFromIndex[i]
+				return nil, nil, asDecodeError(err, fieldCodec).within(name).prefixed("cannot decode binary record %q field %q", c.typeName, name)
+			}
+			recordMap[nameFromIndex[i]] = value
+		}
+		return recordMap, buf, nil
+	}
+
+	c.skipBinary = func(buf []byte) ([]byte, error) {
+		for i, fieldCodec := range codecFromIndex {
+			var err error
+			if buf, err = fieldCodec.skipBinary(buf); err != nil {
+				name := nameFromIndex[i]
+				return nil, asDecodeError(err, fieldCodec).within(name).prefixed("cannot skip binary record %q field %q", c.typeName, name)
+			}
+		}
+		return buf, nil
+	}
+
 	c.nativeFromTextual = func(buf []byte) (interface{}, []byte, error) {
 		var mapValues map[string]interface{}
 		var err error
@@ -123,13 +220,17 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 		// codecFromFieldName map.
 		mapValues, buf, err = genericMapTextDecoder(buf, nil, codecFromFieldName)
 		if err != nil {
-			return nil, nil, fmt.Errorf("cannot decode textual record %q: %s", c.typeName, err)
+			return nil, nil, asDecodeError(err, c).prefixed("cannot decode textual record %q", c.typeName)
 		}
 		if actual, expected := len(mapValues), len(codecFromFieldName); actual != expected {
 			// set missing field keys to their respective default values, then
 			// re-check number of keys
-			for fieldName, defaultValue := range defaultValueFromName {
+			for fieldName, encoded := range defaultBinaryFromName {
 				if _, ok := mapValues[fieldName]; !ok {
+					defaultValue, _, err := codecFromFieldName[fieldName].nativeFromBinary(append([]byte(nil), encoded...))
+					if err != nil {
+						return nil, nil, fmt.Errorf("cannot decode textual record %q field %q default value: %s", c.typeName, fieldName, err)
+					}
 					mapValues[fieldName] = defaultValue
 				}
 			}
@@ -148,6 +249,11 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 		if !ok {
 			return nil, fmt.Errorf("cannot encode textual record %q: expected map[string]interface{}; received: %T", c.typeName, datum)
 		}
+		if c.config.strictFields {
+			if unknown := unknownFieldNames(sourceMap, codecFromFieldName); unknown != nil {
+				return nil, fmt.Errorf("cannot encode textual record %q: unknown fields: %q", c.typeName, unknown)
+			}
+		}
 		destMap := make(map[string]interface{}, len(codecFromIndex))
 		for fieldName := range codecFromFieldName {
 			fieldValue, ok := sourceMap[fieldName]
@@ -164,8 +270,142 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 		// NOTE: Setting `defaultCodec == nil` instructs genericMapTextEncoder
 		// to return an error when a field name is not found in the
 		// codecFromFieldName map.
-		return genericMapTextEncoder(buf, datum, nil, codecFromFieldName)
+		return genericMapTextEncoder(buf, datum, nil, codecFromFieldName, c.config.sortedMapKeys)
 	}
 
 	return c, nil
 }
+
+// unknownFieldNames returns the sorted keys of valueMap that are not names of
+// record fields, or nil when every key is the name of a field.
+func unknownFieldNames(valueMap map[string]interface{}, codecFromFieldName map[string]*Codec) []string {
+	var unknown []string
+	for key := range valueMap {
+		if _, ok := codecFromFieldName[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// CheckDefault returns an error when value, in the form encoding/json decodes
+// the JSON default value of a record field, is not a valid default value of a
+// field whose type is described by the Codec. For instance, the default value
+// of a union ought to be a value of its first member.
+//
+//     codec, err := goavro.NewCodec(`["null","string"]`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     err = codec.CheckDefault("abc") // error, because the first member is null
+func (c *Codec) CheckDefault(value interface{}) error {
+	_, _, err := binaryFromDefault(c, value)
+	return err
+}
+
+// binaryFromDefault returns the binary encoding of the JSON default value of a
+// record field whose type is described by codec c, along with its native form,
+// which is obtained by decoding the binary encoding, so it holds values of the
+// same Go types as decoded data does.
+func binaryFromDefault(c *Codec, value interface{}) ([]byte, interface{}, error) {
+	encodable, err := nativeFromDefault(c.node, value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("default value ought to encode using field schema: %s", err)
+	}
+	encoded, err := c.binaryFromNative(nil, encodable)
+	if err != nil {
+		return nil, nil, fmt.Errorf("default value ought to encode using field schema: %s", err)
+	}
+	native, _, err := c.nativeFromBinary(append([]byte(nil), encoded...))
+	if err != nil {
+		return nil, nil, fmt.Errorf("default value ought to decode using field schema: %s", err)
+	}
+	return encoded, native, nil
+}
+
+// nativeFromDefault returns the JSON default value of a record field of the
+// specified schema converted to a form its binary encoder accepts. As
+// specified by Avro, the default value of a union is a value of its first
+// member, and the default values of bytes and fixed are strings whose code
+// points, between 0 and 255, are the bytes. Values that do not match the
+// schema are returned as they are, for the encoder to report.
+func nativeFromDefault(node Schema, value interface{}) (interface{}, error) {
+	switch s := node.(type) {
+	case *UnionSchema:
+		first := s.members[0]
+		datum, err := nativeFromDefault(first, value)
+		if err != nil {
+			return nil, err
+		}
+		return Union(first.codec().typeName.fullName, datum), nil
+	case *PrimitiveSchema:
+		if s.typeName == "bytes" {
+			return bytesFromDefault(value)
+		}
+	case *FixedSchema:
+		return bytesFromDefault(value)
+	case *ArraySchema:
+		items, ok := value.([]interface{})
+		if !ok {
+			break
+		}
+		datum := make([]interface{}, len(items))
+		for i, item := range items {
+			var err error
+			if datum[i], err = nativeFromDefault(s.items, item); err != nil {
+				return nil, err
+			}
+		}
+		return datum, nil
+	case *MapSchema:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			break
+		}
+		datum := make(map[string]interface{}, len(values))
+		for key, v := range values {
+			var err error
+			if datum[key], err = nativeFromDefault(s.values, v); err != nil {
+				return nil, err
+			}
+		}
+		return datum, nil
+	case *RecordSchema:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			break
+		}
+		datum := make(map[string]interface{}, len(values))
+		for key, v := range values {
+			datum[key] = v
+		}
+		for _, field := range s.fields {
+			if v, ok := values[field.name]; ok {
+				var err error
+				if datum[field.name], err = nativeFromDefault(field.schema, v); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return datum, nil
+	}
+	return value, nil
+}
+
+// bytesFromDefault returns the bytes of the default value of a bytes or fixed
+// field, whose code points ought to be between 0 and 255.
+func bytesFromDefault(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 255 {
+			return nil, fmt.Errorf("cannot encode bytes: code point ought to be between 0 and 255: %U", r)
+		}
+		b = append(b, byte(r))
+	}
+	return b, nil
+}
@@ -3,6 +3,7 @@ package goavro_test
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/karrick/goavro"
@@ -397,6 +398,88 @@ func TestRecordFieldDefaultValue(t *testing.T) {
 		"default value ought to encode using field schema")
 }
 
+func TestRecordFieldDefaultValueBytes(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[{"name":"f1","type":"bytes","default":"\u00ff\u0001"},{"name":"f2","type":{"type":"fixed","name":"f2","size":2},"default":"ab"}]}`
+	testBinaryEncodePass(t, schema, map[string]interface{}{}, []byte{4, 0xff, 1, 'a', 'b'})
+	testTextDecodePass(t, schema, map[string]interface{}{"f1": []byte{0xff, 1}, "f2": []byte("ab")}, []byte(`{}`))
+	testSchemaInvalid(t,
+		`{"type":"record","name":"r1","fields":[{"name":"f1","type":"bytes","default":"\u2318"}]}`,
+		"default value ought to encode using field schema: cannot encode bytes: code point ought to be between 0 and 255: U+2318")
+	testSchemaInvalid(t,
+		`{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"fixed","name":"f1","size":2},"default":"abc"}]}`,
+		"default value ought to encode using field schema")
+}
+
+func TestRecordFieldDefaultValueNativeTypes(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[
+  {"name":"int","type":"int","default":13},
+  {"name":"long","type":"long","default":-13},
+  {"name":"float","type":"float","default":1.5},
+  {"name":"enum","type":{"type":"enum","name":"e1","symbols":["alpha","bravo"]},"default":"bravo"},
+  {"name":"union","type":["long","null"],"default":42},
+  {"name":"array","type":{"type":"array","items":"bytes"},"default":["a"]},
+  {"name":"map","type":{"type":"map","values":"int"},"default":{"a":1}},
+  {"name":"record","type":{"type":"record","name":"r2","fields":[{"name":"a","type":"bytes"},{"name":"b","type":"int","default":2}]},"default":{"a":"\u00ff"}}
+]}`
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"int":    int32(13),
+		"long":   int64(-13),
+		"float":  float32(1.5),
+		"enum":   "bravo",
+		"union":  map[string]interface{}{"long": int64(42)},
+		"array":  []interface{}{[]byte("a")},
+		"map":    map[string]interface{}{"a": int32(1)},
+		"record": map[string]interface{}{"a": []byte{0xff}, "b": int32(2)},
+	}
+	datum, _, err := codec.NativeFromTextual([]byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(datum, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", datum, expected)
+	}
+
+	// decoded defaults do not share maps or slices
+	datum.(map[string]interface{})["map"].(map[string]interface{})["a"] = int32(2)
+	datum.(map[string]interface{})["array"].([]interface{})[0].([]byte)[0] = 'b'
+	datum, _, err = codec.NativeFromTextual([]byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(datum, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", datum, expected)
+	}
+
+	field, _ := codec.SchemaTree().(*goavro.RecordSchema).Field("record")
+	if value, _ := field.Default(); !reflect.DeepEqual(value, expected["record"]) {
+		t.Errorf("Actual: %#v; Expected: %#v", value, expected["record"])
+	}
+
+	// missing fields encode their default values
+	buf, err := codec.BinaryFromNative(nil, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedBuf, err := codec.BinaryFromNative(nil, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, expectedBuf) {
+		t.Errorf("Actual: %#v; Expected: %#v", buf, expectedBuf)
+	}
+	if _, err = codec.TextualFromNative(nil, map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	testSchemaInvalid(t,
+		`{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"enum","name":"e1","symbols":["alpha"]},"default":"bravo"}]}`,
+		"default value ought to encode using field schema")
+}
+
 func TestRecordFieldUnionDefaultValue(t *testing.T) {
 	testSchemaValid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["int","null"],"default":13}]}`)
 	testSchemaValid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","int"],"default":null}]}`)
@@ -626,3 +709,23 @@ func ExampleTextualFromNative() {
 	fmt.Printf("%s", text)
 	// Output: {"next":{"LongList":{"next":{"LongList":{"next":null}}}}}
 }
+
+func TestCodecCheckDefault(t *testing.T) {
+	codec, err := goavro.NewCodec(`["null","string"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = codec.CheckDefault(nil); err != nil {
+		t.Error(err)
+	}
+	ensureError(t, codec.CheckDefault("abc"), "default value ought to encode using field schema")
+
+	codec, err = goavro.NewCodec(`{"type":"fixed","name":"f1","size":2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = codec.CheckDefault("ÿ\u0000"); err != nil {
+		t.Error(err)
+	}
+	ensureError(t, codec.CheckDefault("ÿ"), "default value ought to encode using field schema")
+}
@@ -0,0 +1,111 @@
+package goavro
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// typeDefinition holds the parsed schema of a named type, along with its
+// namespace, which is the enclosing namespace of the names it refers to.
+type typeDefinition struct {
+	schemaMap map[string]interface{}
+	namespace string
+}
+
+// TypeRegistry holds named types defined by one or more schemas, so other
+// schemas may refer to them without defining them. For instance, a schema
+// defining the com.acme.Address record may be registered, after which schemas
+// of Codecs created using the WithRegistry option may refer to
+// "com.acme.Address", or simply to "Address" inside the com.acme namespace.
+//
+// Each Codec built using a TypeRegistry receives its own copy of the named
+// types it refers to, so types registered afterwards do not affect existing
+// Codecs. A TypeRegistry may be safely used by many go routines
+// simultaneously.
+//
+//     registry := goavro.NewTypeRegistry()
+//     if err := registry.Register(addressSchema); err != nil {
+//             fmt.Println(err)
+//     }
+//     codec, err := goavro.NewCodecWithOptions(customerSchema, goavro.WithRegistry(registry))
+type TypeRegistry struct {
+	lock        sync.RWMutex
+	definitions map[string]typeDefinition
+}
+
+// NewTypeRegistry returns an empty TypeRegistry.
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{definitions: make(map[string]typeDefinition)}
+}
+
+// Register adds the named types defined by the schema to the registry. The
+// schema may refer to types already registered. Registering a type that is
+// already registered with an identical definition has no effect, while
+// registering a type whose definition conflicts with the registered one
+// returns an error, and registers none of the types defined by the schema.
+func (r *TypeRegistry) Register(schemaSpecification string, options ...CodecOption) error {
+	config := &codecConfig{registry: r}
+	for _, option := range options {
+		option(config)
+	}
+	_, st, err := newCodec(schemaSpecification, config)
+	if err != nil {
+		return fmt.Errorf("cannot register schema: %w", err)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	// NOTE: Check every definition before registering any of them, because
+	// another schema may have been registered while building this one.
+	for fullName, definition := range st.definitions {
+		if err = r.checkDefinitionLocked(fullName, definition.schemaMap); err != nil {
+			return fmt.Errorf("cannot register schema: %w", err)
+		}
+	}
+	for fullName, definition := range st.definitions {
+		r.definitions[fullName] = definition
+	}
+	return nil
+}
+
+// Names returns the sorted full names of the registered types.
+func (r *TypeRegistry) Names() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	names := make([]string, 0, len(r.definitions))
+	for fullName := range r.definitions {
+		names = append(names, fullName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup returns the definition of the type referred to by typeName within
+// the enclosing namespace.
+func (r *TypeRegistry) lookup(typeName, enclosingNamespace string) (typeDefinition, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, fullName := range candidateFullNames(typeName, enclosingNamespace) {
+		if definition, ok := r.definitions[fullName]; ok {
+			return definition, true
+		}
+	}
+	return typeDefinition{}, false
+}
+
+// checkDefinition returns an error when a type with the specified full name is
+// registered with a definition different than schemaMap.
+func (r *TypeRegistry) checkDefinition(fullName string, schemaMap map[string]interface{}) error {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.checkDefinitionLocked(fullName, schemaMap)
+}
+
+func (r *TypeRegistry) checkDefinitionLocked(fullName string, schemaMap map[string]interface{}) error {
+	if registered, ok := r.definitions[fullName]; ok && !reflect.DeepEqual(registered.schemaMap, schemaMap) {
+		return fmt.Errorf("type %q ought not conflict with its registered definition", fullName)
+	}
+	return nil
+}
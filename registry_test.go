@@ -0,0 +1,147 @@
+package goavro_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+const (
+	registryAddressSchema = `{"type":"record","name":"Address","namespace":"com.acme","fields":[
+		{"name":"street","type":"string"},
+		{"name":"country","type":{"type":"enum","name":"Country","symbols":["US","CA"]}}
+	]}`
+	registryCustomerSchema = `{"type":"record","name":"Customer","namespace":"com.acme","fields":[
+		{"name":"name","type":"string"},
+		{"name":"home","type":"Address"},
+		{"name":"work","type":["null","com.acme.Address"],"default":null}
+	]}`
+)
+
+func TestTypeRegistryResolvesReferences(t *testing.T) {
+	_, err := goavro.NewCodec(registryCustomerSchema)
+	ensureError(t, err, "unknown type name")
+
+	registry := goavro.NewTypeRegistry()
+	if err = registry.Register(registryAddressSchema); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := registry.Names(), []string{"com.acme.Address", "com.acme.Country"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	codec, err := goavro.NewCodecWithOptions(registryCustomerSchema, goavro.WithRegistry(registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := map[string]interface{}{
+		"name": "Ann",
+		"home": map[string]interface{}{"street": "1 Main St", "country": "CA"},
+		"work": goavro.Union("com.acme.Address", map[string]interface{}{"street": "2 Side St", "country": "US"}),
+	}
+	buf, err := codec.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, datum) {
+		t.Errorf("Actual: %#v; Expected: %#v", decoded, datum)
+	}
+
+	// the Customer schema may itself be registered, because its references
+	// are resolved against the registry
+	if err = registry.Register(registryCustomerSchema); err != nil {
+		t.Fatal(err)
+	}
+	codec, err = goavro.NewCodecWithOptions(`{"type":"array","items":"com.acme.Customer"}`, goavro.WithRegistry(registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = codec.BinaryFromNative(nil, []interface{}{datum}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTypeRegistryConflicts(t *testing.T) {
+	registry := goavro.NewTypeRegistry()
+	if err := registry.Register(registryAddressSchema); err != nil {
+		t.Fatal(err)
+	}
+	// identical redefinition is allowed
+	if err := registry.Register(registryAddressSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	conflicting := `{"type":"record","name":"com.acme.Shipment","fields":[
+		{"name":"to","type":{"type":"record","name":"com.acme.Address","fields":[{"name":"line1","type":"string"}]}}
+	]}`
+	err := registry.Register(conflicting)
+	ensureError(t, err, `type "com.acme.Address" ought not conflict with its registered definition`)
+	if actual, expected := registry.Names(), []string{"com.acme.Address", "com.acme.Country"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	// codecs using the registry may not redefine registered types either
+	_, err = goavro.NewCodecWithOptions(conflicting, goavro.WithRegistry(registry))
+	ensureError(t, err, "ought not conflict")
+
+	// other registries are not affected
+	if _, err = goavro.NewCodecWithOptions(conflicting, goavro.WithRegistry(goavro.NewTypeRegistry())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTypeRegistryInlineDefinitions(t *testing.T) {
+	registry := goavro.NewTypeRegistry()
+	if err := registry.Register(registryAddressSchema); err != nil {
+		t.Fatal(err)
+	}
+	// Customer defines Address inline, identical to its registered definition
+	customer := `{"type":"record","name":"Customer","namespace":"com.acme","fields":[
+		{"name":"home","type":` + registryAddressSchema + `}
+	]}`
+	if err := registry.Register(customer); err != nil {
+		t.Fatal(err)
+	}
+
+	// references resolve regardless of their order
+	for _, schema := range []string{
+		`{"type":"record","name":"com.acme.Order","fields":[{"name":"a","type":"Address"},{"name":"c","type":"Customer"}]}`,
+		`{"type":"record","name":"com.acme.Order","fields":[{"name":"c","type":"Customer"},{"name":"a","type":"Address"}]}`,
+	} {
+		codec, err := goavro.NewCodecWithOptions(schema, goavro.WithRegistry(registry))
+		if err != nil {
+			t.Fatalf("schema: %s; %s", schema, err)
+		}
+		address := map[string]interface{}{"street": "1 Main St", "country": "US"}
+		if _, err = codec.BinaryFromNative(nil, map[string]interface{}{"a": address, "c": map[string]interface{}{"home": address}}); err != nil {
+			t.Errorf("schema: %s; %s", schema, err)
+		}
+	}
+}
+
+func TestTypeRegistryConcurrentUse(t *testing.T) {
+	registry := goavro.NewTypeRegistry()
+	if err := registry.Register(registryAddressSchema); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := goavro.NewCodecWithOptions(registryCustomerSchema, goavro.WithRegistry(registry)); err != nil {
+				t.Error(err)
+			}
+			if err := registry.Register(registryAddressSchema); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,446 @@
+package goavro
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Schema is a node of the immutable tree describing a parsed Avro schema. Use
+// a type switch on the concrete node types, *PrimitiveSchema, *ArraySchema,
+// *MapSchema, *UnionSchema, *EnumSchema, *FixedSchema, and *RecordSchema, to
+// inspect a node further.
+//
+// A named type is represented by a single node, no matter how many times the
+// schema refers to it. Therefore the tree of a recursive schema, such as a
+// linked list, contains cycles, and tools walking the tree ought to remember
+// which named types they have already visited.
+type Schema interface {
+	// Type returns the Avro type of the node, which is either one of the
+	// primitive type names, or one of "array", "enum", "fixed", "map",
+	// "record", or "union".
+	Type() string
+
+	// codec returns the Codec built for this node of the schema.
+	codec() *Codec
+}
+
+// ParseSchema returns the root of the tree describing the provided Avro schema,
+// after verifying the schema is valid. The schema is validated using the same
+// rules as NewCodec.
+//
+//     schema, err := goavro.ParseSchema(`{"type":"record","name":"r1","fields":[{"name":"f1","type":"int","doc":"first field"}]}`)
+//     if err != nil {
+//             fmt.Println(err)
+//     }
+//     for _, field := range schema.(*goavro.RecordSchema).Fields() {
+//             fmt.Println(field.Name(), field.Schema().Type(), field.Doc())
+//     }
+//     // Output: f1 int first field
+func ParseSchema(schemaSpecification string) (Schema, error) {
+	c, err := NewCodec(schemaSpecification)
+	if err != nil {
+		return nil, err
+	}
+	return c.node, nil
+}
+
+// PrimitiveSchema describes one of the Avro primitive types.
+type PrimitiveSchema struct {
+	c           *Codec
+	typeName    string
+	logicalType string
+}
+
+// Type returns the name of the primitive type, for instance "long".
+func (s *PrimitiveSchema) Type() string { return s.typeName }
+
+// LogicalType returns the logical type the Codec uses to translate values of
+// the primitive type, for instance "timestamp-millis", or the empty string when
+// values are translated as the primitive type. Logical types are only used when
+// the Codec is created with the WithLogicalTypes option.
+func (s *PrimitiveSchema) LogicalType() string { return s.logicalType }
+
+func (s *PrimitiveSchema) codec() *Codec { return s.c }
+
+// ArraySchema describes an Avro array.
+type ArraySchema struct {
+	c     *Codec
+	items Schema
+}
+
+// Type returns "array".
+func (s *ArraySchema) Type() string { return "array" }
+
+// Items returns the schema of the array items.
+func (s *ArraySchema) Items() Schema { return s.items }
+
+func (s *ArraySchema) codec() *Codec { return s.c }
+
+// MapSchema describes an Avro map.
+type MapSchema struct {
+	c      *Codec
+	values Schema
+}
+
+// Type returns "map".
+func (s *MapSchema) Type() string { return "map" }
+
+// Values returns the schema of the map values.
+func (s *MapSchema) Values() Schema { return s.values }
+
+func (s *MapSchema) codec() *Codec { return s.c }
+
+// UnionSchema describes an Avro union.
+type UnionSchema struct {
+	c        *Codec
+	members  []Schema
+	memberOf func(interface{}) (int, interface{}, error) // member encoding a native datum
+}
+
+// Type returns "union".
+func (s *UnionSchema) Type() string { return "union" }
+
+// Members returns the schemas of the union members, in the order they were
+// declared.
+func (s *UnionSchema) Members() []Schema {
+	return append([]Schema(nil), s.members...)
+}
+
+func (s *UnionSchema) codec() *Codec { return s.c }
+
+// Annotated is implemented by the schema nodes that may carry a documentation
+// string and custom properties: *EnumSchema, *FixedSchema, *RecordSchema, and
+// *FieldSchema. Custom properties are the attributes of the node not defined by
+// the Avro specification, such as `"pii": true`, with their values in the form
+// returned by json.Unmarshal.
+type Annotated interface {
+	// Doc returns the documentation string of the node, or the empty string
+	// when the schema does not provide one.
+	Doc() string
+
+	// Properties returns a copy of the custom properties of the node.
+	Properties() map[string]interface{}
+
+	// Property returns a copy of the value of the specified custom property,
+	// and false when the node does not have that property.
+	Property(key string) (interface{}, bool)
+}
+
+// properties holds the custom properties of a schema node.
+type properties map[string]interface{}
+
+// Properties returns a copy of the custom properties of the node.
+func (p properties) Properties() map[string]interface{} {
+	m := make(map[string]interface{}, len(p))
+	for key, value := range p {
+		m[key] = copyNative(value)
+	}
+	return m
+}
+
+// Property returns a copy of the value of the specified custom property, and
+// false when the node does not have that property.
+func (p properties) Property(key string) (interface{}, bool) {
+	value, ok := p[key]
+	return copyNative(value), ok
+}
+
+// propertiesFromSchemaMap returns the attributes of schemaMap other than the
+// specified reserved attributes.
+func propertiesFromSchemaMap(schemaMap map[string]interface{}, reserved ...string) properties {
+	props := make(properties)
+nextAttribute:
+	for key, value := range schemaMap {
+		for _, r := range reserved {
+			if key == r {
+				continue nextAttribute
+			}
+		}
+		props[key] = value
+	}
+	return props
+}
+
+// namedSchema holds the attributes common to the named Avro types: enum,
+// fixed, and record.
+type namedSchema struct {
+	properties
+	c       *Codec
+	aliases []string
+	doc     string
+}
+
+// Name returns the full name of the type, including its namespace.
+func (s *namedSchema) Name() string { return s.c.typeName.fullName }
+
+// Namespace returns the namespace of the type, or the empty string when the
+// type is in the null namespace.
+func (s *namedSchema) Namespace() string { return s.c.typeName.namespace }
+
+// Doc returns the documentation string of the type, or the empty string when
+// the schema does not provide one.
+func (s *namedSchema) Doc() string { return s.doc }
+
+// Aliases returns the alternate names of the type.
+func (s *namedSchema) Aliases() []string { return append([]string(nil), s.aliases...) }
+
+func (s *namedSchema) codec() *Codec { return s.c }
+
+// EnumSchema describes an Avro enum.
+type EnumSchema struct {
+	namedSchema
+	symbols       []string
+	defaultSymbol string
+	hasDefault    bool
+}
+
+// Type returns "enum".
+func (s *EnumSchema) Type() string { return "enum" }
+
+// Symbols returns the symbols of the enum, in the order they were declared.
+func (s *EnumSchema) Symbols() []string { return append([]string(nil), s.symbols...) }
+
+// Default returns the symbol readers use in place of symbols they do not know,
+// and true; or the empty string and false when the enum has no default.
+func (s *EnumSchema) Default() (string, bool) { return s.defaultSymbol, s.hasDefault }
+
+// FixedSchema describes an Avro fixed.
+type FixedSchema struct {
+	namedSchema
+	size uint
+}
+
+// Type returns "fixed".
+func (s *FixedSchema) Type() string { return "fixed" }
+
+// Size returns the number of bytes of each value of the fixed type.
+func (s *FixedSchema) Size() int { return int(s.size) }
+
+// RecordSchema describes an Avro record.
+type RecordSchema struct {
+	namedSchema
+	fields       []*FieldSchema
+	appendFields func([]byte, interface{}, func([]byte, *Codec, interface{}) ([]byte, error)) ([]byte, error)
+}
+
+// Type returns "record".
+func (s *RecordSchema) Type() string { return "record" }
+
+// Fields returns the fields of the record, in the order they were declared.
+func (s *RecordSchema) Fields() []*FieldSchema {
+	return append([]*FieldSchema(nil), s.fields...)
+}
+
+// Field returns the field with the specified name, and false when the record
+// has no such field.
+func (s *RecordSchema) Field(name string) (*FieldSchema, bool) {
+	for _, f := range s.fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Field sort orders, as specified by the Avro specification.
+const (
+	OrderAscending  = "ascending"
+	OrderDescending = "descending"
+	OrderIgnore     = "ignore"
+)
+
+// FieldSchema describes a single field of an Avro record.
+type FieldSchema struct {
+	properties
+	aliases      []string
+	defaultValue interface{}
+	doc          string
+	hasDefault   bool
+	name         string
+	order        string
+	record       *RecordSchema
+	schema       Schema
+}
+
+// Name returns the name of the field.
+func (f *FieldSchema) Name() string { return f.name }
+
+// Record returns the record that declares the field.
+func (f *FieldSchema) Record() *RecordSchema { return f.record }
+
+// Schema returns the schema of the field value.
+func (f *FieldSchema) Schema() Schema { return f.schema }
+
+// Doc returns the documentation string of the field, or the empty string when
+// the schema does not provide one.
+func (f *FieldSchema) Doc() string { return f.doc }
+
+// Aliases returns the alternate names of the field.
+func (f *FieldSchema) Aliases() []string { return append([]string(nil), f.aliases...) }
+
+// Order returns the sort order of the field, which is one of OrderAscending,
+// OrderDescending, or OrderIgnore. Fields without an order attribute sort in
+// ascending order.
+func (f *FieldSchema) Order() string { return f.order }
+
+// Default returns a copy of the default value of the field, in the native Go
+// form accepted by the field's encoder, and true; or nil and false when the
+// field has no default value.
+func (f *FieldSchema) Default() (interface{}, bool) {
+	return copyNative(f.defaultValue), f.hasDefault
+}
+
+// copyNative returns a deep copy of the maps and slices in a native datum, so
+// callers may modify the copy without affecting the original.
+func copyNative(datum interface{}) interface{} {
+	switch v := datum.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = copyNative(value)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, value := range v {
+			a[i] = copyNative(value)
+		}
+		return a
+	case []byte:
+		return append([]byte(nil), v...)
+	case UnionValue:
+		v.Value = copyNative(v.Value)
+		return v
+	default:
+		return datum
+	}
+}
+
+// attributesFromSchemaMap returns the optional doc and aliases attributes
+// shared by named types and record fields. As they do not affect encoding,
+// aliases that are not strings are ignored rather than rejected.
+func attributesFromSchemaMap(schemaMap map[string]interface{}) (string, []string) {
+	doc, _ := schemaMap["doc"].(string)
+	a1, _ := schemaMap["aliases"].([]interface{})
+	var aliases []string
+	for _, a := range a1 {
+		if alias, ok := a.(string); ok {
+			aliases = append(aliases, alias)
+		}
+	}
+	return doc, aliases
+}
+
+// SchemaTree returns the root of the tree describing the schema used to create
+// the Codec.
+func (c *Codec) SchemaTree() Schema {
+	return c.node
+}
+
+// TypeProperties returns the custom properties of the named type with the
+// specified full name, or an error when the schema used to create the Codec
+// does not define that type.
+//
+//     codec, err := goavro.NewCodec(`{"type":"fixed","name":"com.example.token","size":16,"sensitivity":"high"}`)
+//     if err != nil {
+//             fmt.Println(err)
+//     }
+//     props, err := codec.TypeProperties("com.example.token")
+//     if err != nil {
+//             fmt.Println(err)
+//     }
+//     fmt.Println(props["sensitivity"])
+//     // Output: high
+func (c *Codec) TypeProperties(fullName string) (map[string]interface{}, error) {
+	node, ok := c.namedTypes[fullName]
+	if !ok {
+		return nil, fmt.Errorf("cannot find named type: %q", fullName)
+	}
+	return node.(Annotated).Properties(), nil
+}
+
+// FieldProperties returns the custom properties of the specified field of the
+// record type with the specified full name, or an error when the schema used to
+// create the Codec does not define that record or field.
+func (c *Codec) FieldProperties(recordName, fieldName string) (map[string]interface{}, error) {
+	node, ok := c.namedTypes[recordName]
+	if !ok {
+		return nil, fmt.Errorf("cannot find named type: %q", recordName)
+	}
+	record, ok := node.(*RecordSchema)
+	if !ok {
+		return nil, fmt.Errorf("cannot find record: %q is %s", recordName, node.Type())
+	}
+	field, ok := record.Field(fieldName)
+	if !ok {
+		return nil, fmt.Errorf("cannot find record %q field: %q", recordName, fieldName)
+	}
+	return field.Properties(), nil
+}
+
+// splitFieldPath returns the field names of a field path, such as
+// "/order/customer/email". Each name in a field path selects a field of the
+// record reached so far, while array items, map values, and union members are
+// descended into without naming them.
+func splitFieldPath(path string) ([]string, error) {
+	if len(path) < 2 || path[0] != '/' {
+		return nil, fmt.Errorf("field path ought to start with / followed by field name: %q", path)
+	}
+	names := strings.Split(path[1:], "/")
+	for _, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("field path ought not have empty field name: %q", path)
+		}
+	}
+	return names, nil
+}
+
+// recordsWithin returns the records directly reachable from s: s itself when it
+// is a record, and the records found by descending into array items, map
+// values, and union members.
+func recordsWithin(s Schema) []*RecordSchema {
+	switch v := s.(type) {
+	case *RecordSchema:
+		return []*RecordSchema{v}
+	case *ArraySchema:
+		return recordsWithin(v.items)
+	case *MapSchema:
+		return recordsWithin(v.values)
+	case *UnionSchema:
+		var records []*RecordSchema
+		for _, member := range v.members {
+			records = append(records, recordsWithin(member)...)
+		}
+		return records
+	}
+	return nil
+}
+
+// resolveFieldPath returns the fields selected by the field path relative to
+// s, or an error when the path does not select any field.
+func resolveFieldPath(s Schema, path string) ([]*FieldSchema, error) {
+	names, err := splitFieldPath(path)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []Schema{s}
+	var fields []*FieldSchema
+	for _, name := range names {
+		fields = fields[:0]
+		for _, candidate := range candidates {
+			for _, record := range recordsWithin(candidate) {
+				if field, ok := record.Field(name); ok {
+					fields = append(fields, field)
+				}
+			}
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("cannot find field path: %q", path)
+		}
+		candidates = candidates[:0]
+		for _, field := range fields {
+			candidates = append(candidates, field.schema)
+		}
+	}
+	return fields, nil
+}
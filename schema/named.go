@@ -0,0 +1,405 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/karrick/goavro"
+)
+
+// namedType holds the attributes shared by records, enums, and fixed types,
+// along with the first error found while building one.
+type namedType struct {
+	name    string
+	doc     string
+	aliases []string
+	err     error
+}
+
+func newNamedType(kind, name string) namedType {
+	n := namedType{name: name}
+	if err := goavro.CheckFullName(name); err != nil {
+		n.err = fmt.Errorf("%s %q %s", kind, name, err)
+	}
+	return n
+}
+
+func (n *namedType) setAliases(kind string, aliases []string) {
+	for _, alias := range aliases {
+		if err := goavro.CheckFullName(alias); err != nil && n.err == nil {
+			n.err = fmt.Errorf("%s %q alias %q %s", kind, n.name, alias, err)
+		}
+	}
+	n.aliases = append(n.aliases, aliases...)
+}
+
+// define returns the full name of the named type t and the namespace of the
+// types nested in it, when defined within namespace, and whether the schema
+// already defines it. A named type is defined where it first appears, so when
+// used again, possibly within another namespace, the full name of its first
+// definition is returned. It returns an error when the full name is not a valid
+// name of a named type, or when the schema defines a different type with the
+// same full name.
+func (b *builder) define(t Type, name, namespace string) (string, string, bool, error) {
+	if fullName, ok := b.fullNames[t]; ok {
+		return fullName, "", true, nil
+	}
+	fullName := name
+	if index := strings.LastIndexByte(name, '.'); index > -1 {
+		namespace = name[:index]
+	} else if namespace != "" {
+		fullName = namespace + "." + name
+	}
+	if err := goavro.CheckTypeName(fullName); err != nil {
+		return "", "", false, fmt.Errorf("type %q %s", fullName, err)
+	}
+	if _, ok := b.defined[fullName]; ok {
+		return "", "", false, fmt.Errorf("type %q ought to be defined once", fullName)
+	}
+	b.defined[fullName] = t
+	b.fullNames[t] = fullName
+	return fullName, namespace, false, nil
+}
+
+// attributes returns the schema map of the named type, holding its type, name,
+// and optional doc and aliases attributes.
+func (n *namedType) attributes(typeName string) map[string]interface{} {
+	m := map[string]interface{}{"type": typeName, "name": n.name}
+	if n.doc != "" {
+		m["doc"] = n.doc
+	}
+	if len(n.aliases) > 0 {
+		m["aliases"] = n.aliases
+	}
+	return m
+}
+
+////////////////////////////////////////
+// Record
+////////////////////////////////////////
+
+// RecordBuilder builds an Avro record.
+type RecordBuilder struct {
+	namedType
+	fields []*field
+}
+
+type field struct {
+	name         string
+	t            Type
+	doc          string
+	aliases      []string
+	order        string
+	defaultValue interface{}
+	hasDefault   bool
+}
+
+// FieldOption configures a record field added by RecordBuilder.Field.
+type FieldOption func(*field)
+
+// WithDefault returns an option that sets the default value of a field, used
+// by readers when data lacks the field. The default value of a union is a
+// value of its first member, and the default value of a bytes or fixed field
+// may be provided as a []byte.
+func WithDefault(value interface{}) FieldOption {
+	return func(f *field) {
+		f.defaultValue = value
+		f.hasDefault = true
+	}
+}
+
+// WithDoc returns an option that sets the documentation string of a field.
+func WithDoc(doc string) FieldOption {
+	return func(f *field) { f.doc = doc }
+}
+
+// WithAliases returns an option that sets the alternate names of a field.
+func WithAliases(aliases ...string) FieldOption {
+	return func(f *field) { f.aliases = append(f.aliases, aliases...) }
+}
+
+// WithOrder returns an option that sets the sort order of a field, which
+// ought to be one of goavro.OrderAscending, goavro.OrderDescending, or
+// goavro.OrderIgnore.
+func WithOrder(order string) FieldOption {
+	return func(f *field) { f.order = order }
+}
+
+// Record returns a builder of an Avro record with the specified name, which
+// may include its namespace, such as "com.acme.User". Records ought to have
+// one or more fields, added using the Field method.
+func Record(name string) *RecordBuilder {
+	return &RecordBuilder{namedType: newNamedType("record", name)}
+}
+
+// Doc sets the documentation string of the record.
+func (r *RecordBuilder) Doc(doc string) *RecordBuilder {
+	r.doc = doc
+	return r
+}
+
+// Aliases adds alternate names of the record.
+func (r *RecordBuilder) Aliases(aliases ...string) *RecordBuilder {
+	r.setAliases("record", aliases)
+	return r
+}
+
+// Field appends a field with the specified name and type to the record. The
+// name of the field and its order are checked as the field is added, and its
+// default value when the JSON specification or the Codec of the schema is
+// created.
+func (r *RecordBuilder) Field(name string, t Type, options ...FieldOption) *RecordBuilder {
+	if r.err != nil {
+		return r
+	}
+	f := &field{name: name, t: t}
+	for _, option := range options {
+		option(f)
+	}
+	if err := goavro.CheckName(name); err != nil {
+		r.err = fmt.Errorf("record %q field %q %s", r.name, name, err)
+		return r
+	}
+	for _, other := range r.fields {
+		if other.name == name {
+			r.err = fmt.Errorf("record %q field name ought to be unique: %q", r.name, name)
+			return r
+		}
+	}
+	for _, alias := range f.aliases {
+		if err := goavro.CheckName(alias); err != nil {
+			r.err = fmt.Errorf("record %q field %q alias %q %s", r.name, name, alias, err)
+			return r
+		}
+	}
+	switch f.order {
+	case "", goavro.OrderAscending, goavro.OrderDescending, goavro.OrderIgnore:
+	default:
+		r.err = fmt.Errorf("record %q field %q order ought to be one of %q, %q, or %q: %q", r.name, name, goavro.OrderAscending, goavro.OrderDescending, goavro.OrderIgnore, f.order)
+		return r
+	}
+	if f.hasDefault {
+		f.defaultValue = jsonFromDefault(f.defaultValue)
+	}
+	r.fields = append(r.fields, f)
+	return r
+}
+
+// JSON returns the JSON specification of the record.
+func (r *RecordBuilder) JSON() (string, error) { return JSON(r) }
+
+// Codec returns a Codec for the record, created with the provided options.
+func (r *RecordBuilder) Codec(options ...goavro.CodecOption) (*goavro.Codec, error) {
+	return Codec(r, options...)
+}
+
+func (r *RecordBuilder) build(b *builder, namespace string) (interface{}, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.fields) == 0 {
+		return nil, fmt.Errorf("record %q ought to have one or more fields", r.name)
+	}
+	fullName, namespace, defined, err := b.define(r, r.name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if defined {
+		return fullName, nil
+	}
+	fields := make([]interface{}, len(r.fields))
+	for i, f := range r.fields {
+		t, err := f.t.build(b, namespace)
+		if err != nil {
+			return nil, err
+		}
+		m := map[string]interface{}{"name": f.name, "type": t}
+		if f.doc != "" {
+			m["doc"] = f.doc
+		}
+		if len(f.aliases) > 0 {
+			m["aliases"] = f.aliases
+		}
+		if f.order != "" {
+			m["order"] = f.order
+		}
+		if f.hasDefault {
+			m["default"] = f.defaultValue
+			b.defaults = true
+		}
+		fields[i] = m
+	}
+	m := r.attributes("record")
+	m["fields"] = fields
+	return m, nil
+}
+
+// jsonFromDefault returns a default value in the form its JSON specification
+// takes, where the bytes of bytes and fixed values are the code points of a
+// string.
+func jsonFromDefault(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		runes := make([]rune, len(v))
+		for i, b := range v {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = jsonFromDefault(item)
+		}
+		return values
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			values[key] = jsonFromDefault(item)
+		}
+		return values
+	}
+	return value
+}
+
+////////////////////////////////////////
+// Enum
+////////////////////////////////////////
+
+// EnumBuilder builds an Avro enum.
+type EnumBuilder struct {
+	namedType
+	symbols       []string
+	defaultSymbol string
+}
+
+// Enum returns a builder of an Avro enum with the specified name, which may
+// include its namespace, and symbols.
+func Enum(name string, symbols ...string) *EnumBuilder {
+	e := &EnumBuilder{namedType: newNamedType("enum", name), symbols: symbols}
+	if e.err == nil && len(symbols) == 0 {
+		e.err = fmt.Errorf("enum %q ought to have one or more symbols", name)
+	}
+	seen := make(map[string]struct{}, len(symbols))
+	for _, symbol := range symbols {
+		if e.err != nil {
+			break
+		}
+		if err := goavro.CheckName(symbol); err != nil {
+			e.err = fmt.Errorf("enum %q symbol %q %s", name, symbol, err)
+		} else if _, ok := seen[symbol]; ok {
+			e.err = fmt.Errorf("enum %q symbol ought to be unique: %q", name, symbol)
+		}
+		seen[symbol] = struct{}{}
+	}
+	return e
+}
+
+// Doc sets the documentation string of the enum.
+func (e *EnumBuilder) Doc(doc string) *EnumBuilder {
+	e.doc = doc
+	return e
+}
+
+// Aliases adds alternate names of the enum.
+func (e *EnumBuilder) Aliases(aliases ...string) *EnumBuilder {
+	e.setAliases("enum", aliases)
+	return e
+}
+
+// Default sets the symbol readers use in place of symbols they do not know,
+// which ought to be one of the symbols of the enum.
+func (e *EnumBuilder) Default(symbol string) *EnumBuilder {
+	for _, s := range e.symbols {
+		if s == symbol {
+			e.defaultSymbol = symbol
+			return e
+		}
+	}
+	if e.err == nil {
+		e.err = fmt.Errorf("enum %q default ought to be member of symbols: %v; %q", e.name, e.symbols, symbol)
+	}
+	return e
+}
+
+// JSON returns the JSON specification of the enum.
+func (e *EnumBuilder) JSON() (string, error) { return JSON(e) }
+
+// Codec returns a Codec for the enum, created with the provided options.
+func (e *EnumBuilder) Codec(options ...goavro.CodecOption) (*goavro.Codec, error) {
+	return Codec(e, options...)
+}
+
+func (e *EnumBuilder) build(b *builder, namespace string) (interface{}, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	fullName, _, defined, err := b.define(e, e.name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if defined {
+		return fullName, nil
+	}
+	m := e.attributes("enum")
+	m["symbols"] = e.symbols
+	if e.defaultSymbol != "" {
+		m["default"] = e.defaultSymbol
+	}
+	return m, nil
+}
+
+////////////////////////////////////////
+// Fixed
+////////////////////////////////////////
+
+// FixedBuilder builds an Avro fixed type.
+type FixedBuilder struct {
+	namedType
+	size int
+}
+
+// Fixed returns a builder of an Avro fixed type with the specified name, which
+// may include its namespace, and size in bytes.
+func Fixed(name string, size int) *FixedBuilder {
+	f := &FixedBuilder{namedType: newNamedType("fixed", name), size: size}
+	if f.err == nil && size <= 0 {
+		f.err = fmt.Errorf("fixed %q size ought to be greater than zero: %d", name, size)
+	}
+	return f
+}
+
+// Doc sets the documentation string of the fixed type.
+func (f *FixedBuilder) Doc(doc string) *FixedBuilder {
+	f.doc = doc
+	return f
+}
+
+// Aliases adds alternate names of the fixed type.
+func (f *FixedBuilder) Aliases(aliases ...string) *FixedBuilder {
+	f.setAliases("fixed", aliases)
+	return f
+}
+
+// JSON returns the JSON specification of the fixed type.
+func (f *FixedBuilder) JSON() (string, error) { return JSON(f) }
+
+// Codec returns a Codec for the fixed type, created with the provided options.
+func (f *FixedBuilder) Codec(options ...goavro.CodecOption) (*goavro.Codec, error) {
+	return Codec(f, options...)
+}
+
+func (f *FixedBuilder) build(b *builder, namespace string) (interface{}, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	fullName, _, defined, err := b.define(f, f.name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if defined {
+		return fullName, nil
+	}
+	m := f.attributes("fixed")
+	m["size"] = f.size
+	return m, nil
+}
@@ -0,0 +1,208 @@
+// Package schema builds Avro schemas programmatically, and produces their JSON
+// specification, along with Codecs for them, so schemas need not be written as
+// JSON strings by hand.
+//
+//     user := schema.Record("com.acme.User").
+//         Field("id", schema.Long()).
+//         Field("email", schema.Optional(schema.String()), schema.WithDefault(nil))
+//     codec, err := user.Codec()
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//
+// Names and symbols are checked as the schema is built, and field default
+// values when its JSON specification or Codec is created. The first error found
+// is retained by the builder, and returned by JSON and Codec.
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/karrick/goavro"
+)
+
+// Type is an Avro type being built. Types are created by the functions of this
+// package, and may be used more than once, including named types, which are
+// defined where they first appear, within the enclosing namespace when their
+// name has none, and referred to by that full name elsewhere, even within other
+// namespaces.
+type Type interface {
+	// build returns the value describing the type, as decoded from its JSON
+	// specification, for use within namespace.
+	build(b *builder, namespace string) (interface{}, error)
+}
+
+// builder records the named types defined while building a schema, so each is
+// defined exactly once.
+type builder struct {
+	defined    map[string]Type // named types by full name
+	fullNames  map[Type]string // full names of named types where first defined
+	references bool            // schema refers to types it does not define
+	defaults   bool            // schema has default values of record fields
+}
+
+func newBuilder() *builder {
+	return &builder{defined: make(map[string]Type), fullNames: make(map[Type]string)}
+}
+
+// JSON returns the JSON specification of t. The default values of record
+// fields are checked using a Codec for t, which checks each against the Codec
+// of its field type, unless t refers to named types it does not define, in
+// which case they are checked when its Codec is created.
+func JSON(t Type) (string, error) {
+	spec, b, err := build(t)
+	if err != nil {
+		return "", err
+	}
+	if b.defaults && !b.references {
+		if _, err = goavro.NewCodec(spec); err != nil {
+			return "", err
+		}
+	}
+	return spec, nil
+}
+
+// Codec returns a Codec for t, created with the provided options.
+func Codec(t Type, options ...goavro.CodecOption) (*goavro.Codec, error) {
+	spec, _, err := build(t)
+	if err != nil {
+		return nil, err
+	}
+	return goavro.NewCodecWithOptions(spec, options...)
+}
+
+// build returns the JSON specification of t, along with the builder used to
+// build it.
+func build(t Type) (string, *builder, error) {
+	b := newBuilder()
+	value, err := t.build(b, "")
+	if err != nil {
+		return "", nil, err
+	}
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot encode schema: %s", err)
+	}
+	return string(buf), b, nil
+}
+
+type primitive string
+
+func (p primitive) build(_ *builder, _ string) (interface{}, error) { return string(p), nil }
+
+// Null returns the Avro null type.
+func Null() Type { return primitive("null") }
+
+// Boolean returns the Avro boolean type.
+func Boolean() Type { return primitive("boolean") }
+
+// Int returns the Avro int type.
+func Int() Type { return primitive("int") }
+
+// Long returns the Avro long type.
+func Long() Type { return primitive("long") }
+
+// Float returns the Avro float type.
+func Float() Type { return primitive("float") }
+
+// Double returns the Avro double type.
+func Double() Type { return primitive("double") }
+
+// Bytes returns the Avro bytes type.
+func Bytes() Type { return primitive("bytes") }
+
+// String returns the Avro string type.
+func String() Type { return primitive("string") }
+
+type logical struct {
+	t           Type
+	logicalType string
+}
+
+// Logical returns t annotated with the specified logical type, such as
+// Logical(Long(), "timestamp-millis").
+func Logical(t Type, logicalType string) Type { return logical{t: t, logicalType: logicalType} }
+
+func (l logical) build(b *builder, namespace string) (interface{}, error) {
+	value, err := l.t.build(b, namespace)
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case string:
+		return map[string]interface{}{"type": v, "logicalType": l.logicalType}, nil
+	case map[string]interface{}:
+		v["logicalType"] = l.logicalType
+		return v, nil
+	}
+	return nil, fmt.Errorf("cannot annotate union with logical type: %q", l.logicalType)
+}
+
+type reference string
+
+// Ref returns a reference to the named type with the specified name, which is
+// defined elsewhere in the schema, or by a goavro.TypeRegistry provided to
+// Codec using the goavro.WithRegistry option.
+func Ref(name string) Type { return reference(name) }
+
+func (r reference) build(b *builder, _ string) (interface{}, error) {
+	if err := goavro.CheckFullName(string(r)); err != nil {
+		return nil, fmt.Errorf("reference %q %s", string(r), err)
+	}
+	b.references = true
+	return string(r), nil
+}
+
+type array struct{ items Type }
+
+// Array returns an Avro array whose items are of type items.
+func Array(items Type) Type { return array{items: items} }
+
+func (a array) build(b *builder, namespace string) (interface{}, error) {
+	items, err := a.items.build(b, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"type": "array", "items": items}, nil
+}
+
+type mapType struct{ values Type }
+
+// Map returns an Avro map whose values are of type values.
+func Map(values Type) Type { return mapType{values: values} }
+
+func (m mapType) build(b *builder, namespace string) (interface{}, error) {
+	values, err := m.values.build(b, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"type": "map", "values": values}, nil
+}
+
+type union []Type
+
+// Union returns an Avro union of members. Default values of record fields of
+// a union type are values of its first member.
+func Union(members ...Type) Type { return union(members) }
+
+// Optional returns a union of null and t, whose default value is null.
+func Optional(t Type) Type { return union{Null(), t} }
+
+func (u union) build(b *builder, namespace string) (interface{}, error) {
+	if len(u) == 0 {
+		return nil, errors.New("union ought to have one or more members")
+	}
+	members := make([]interface{}, len(u))
+	for i, member := range u {
+		if _, ok := member.(union); ok {
+			return nil, fmt.Errorf("union member %d ought not be union", i+1)
+		}
+		var err error
+		if members[i], err = member.build(b, namespace); err != nil {
+			return nil, err
+		}
+	}
+	return members, nil
+}
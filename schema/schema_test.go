@@ -0,0 +1,229 @@
+package schema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+	"github.com/karrick/goavro/schema"
+)
+
+func ensureError(tb testing.TB, err error, contains string) {
+	tb.Helper()
+	if err == nil || !strings.Contains(err.Error(), contains) {
+		tb.Errorf("Actual: %v; Expected: %s", err, contains)
+	}
+}
+
+func TestRecordJSON(t *testing.T) {
+	spec, err := schema.Record("com.acme.User").
+		Doc("a user").
+		Field("id", schema.Long()).
+		Field("email", schema.Optional(schema.String()), schema.WithDefault(nil)).
+		Field("created", schema.Logical(schema.Long(), "timestamp-millis"), schema.WithOrder(goavro.OrderDescending)).
+		JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"doc":"a user","fields":[{"name":"id","type":"long"},{"default":null,"name":"email","type":["null","string"]},{"name":"created","order":"descending","type":{"logicalType":"timestamp-millis","type":"long"}}],"name":"com.acme.User","type":"record"}`
+	if spec != expected {
+		t.Errorf("Actual: %s; Expected: %s", spec, expected)
+	}
+}
+
+func TestRecordCodec(t *testing.T) {
+	codec, err := schema.Record("com.acme.User").
+		Field("id", schema.Long()).
+		Field("email", schema.Optional(schema.String()), schema.WithDefault(nil)).
+		Field("tags", schema.Array(schema.String()), schema.WithDefault([]interface{}{})).
+		Field("key", schema.Bytes(), schema.WithDefault([]byte{0, 0xff})).
+		Codec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, map[string]interface{}{"id": 13})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"id":    int64(13),
+		"email": nil,
+		"tags":  []interface{}{},
+		"key":   []byte{0, 0xff},
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Actual: %v; Expected: %v", decoded, expected)
+	}
+}
+
+func TestNamedTypeReuse(t *testing.T) {
+	status := schema.Enum("Status", "ACTIVE", "DISABLED").Default("ACTIVE")
+	hash := schema.Fixed("MD5", 16)
+	spec, err := schema.Record("com.acme.Account").
+		Field("status", status).
+		Field("previous", schema.Optional(status), schema.WithDefault(nil)).
+		Field("hashes", schema.Map(hash)).
+		Field("hash", hash).
+		JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// named types are defined where they first appear, in the enclosing
+	// namespace, and referred to by full name after that
+	for _, expected := range []string{
+		`{"default":"ACTIVE","name":"Status","symbols":["ACTIVE","DISABLED"],"type":"enum"}`,
+		`"type":["null","com.acme.Status"]`,
+		`{"name":"MD5","size":16,"type":"fixed"}`,
+		`"type":"com.acme.MD5"`,
+	} {
+		if !strings.Contains(spec, expected) {
+			t.Errorf("Actual: %s; Expected: %s", spec, expected)
+		}
+	}
+	if _, err = goavro.NewCodec(spec); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNamedTypeReuseAcrossNamespaces(t *testing.T) {
+	color := schema.Enum("Color", "RED", "GREEN")
+	spec, err := schema.Record("x.Pair").
+		Field("a", schema.Record("a.A").Field("color", color)).
+		Field("b", schema.Record("b.B").Field("color", color)).
+		JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// defined once, in the namespace where it first appears
+	if actual, expected := strings.Count(spec, `"symbols"`), 1; actual != expected {
+		t.Errorf("Actual: %d; Expected: %d; %s", actual, expected, spec)
+	}
+	if expected := `"fields":[{"name":"color","type":"a.Color"}],"name":"b.B"`; !strings.Contains(spec, expected) {
+		t.Errorf("Actual: %s; Expected: %s", spec, expected)
+	}
+	if _, err = goavro.NewCodec(spec); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecursiveRecord(t *testing.T) {
+	node := schema.Record("LinkedList")
+	node.Field("value", schema.Int()).
+		Field("next", schema.Optional(node), schema.WithDefault(nil))
+	codec, err := node.Codec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := map[string]interface{}{
+		"value": int32(1),
+		"next":  goavro.Union("LinkedList", map[string]interface{}{"value": int32(2), "next": nil}),
+	}
+	buf, err := codec.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, datum) {
+		t.Errorf("Actual: %v; Expected: %v", decoded, datum)
+	}
+}
+
+func TestRef(t *testing.T) {
+	spec, err := schema.JSON(schema.Array(schema.Ref("com.acme.User")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"items":"com.acme.User","type":"array"}`; spec != expected {
+		t.Errorf("Actual: %s; Expected: %s", spec, expected)
+	}
+	_, err = schema.JSON(schema.Ref("com.acme-corp.User"))
+	ensureError(t, err, `reference "com.acme-corp.User" schema name ought to have second and remaining characters contain only [A-Za-z0-9_]: acme-corp`)
+}
+
+func TestBuilderNameErrors(t *testing.T) {
+	_, err := schema.Record("com..User").Field("id", schema.Long()).JSON()
+	ensureError(t, err, `record "com..User" schema name ought to be non-empty string`)
+
+	_, err = schema.Record("com.acme.long").Field("id", schema.Long()).JSON()
+	ensureError(t, err, `type "com.acme.long" schema name ought to not redefine primitive type: com.acme.long`)
+
+	// complex type names are only reserved in the null namespace
+	_, err = schema.Record("record").Field("id", schema.Long()).JSON()
+	ensureError(t, err, `type "record" schema name ought to not redefine complex type: record`)
+
+	_, err = schema.Record("com.acme.Holder").Field("map", schema.Fixed("map", 4)).JSON()
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = schema.Record("User").JSON()
+	ensureError(t, err, `record "User" ought to have one or more fields`)
+
+	_, err = schema.Record("User").Field("1d", schema.Long()).JSON()
+	ensureError(t, err, `record "User" field "1d" schema name ought to start with [A-Za-z_]: 1d`)
+
+	_, err = schema.Record("User").Field("id", schema.Long()).Field("id", schema.Int()).JSON()
+	ensureError(t, err, `record "User" field name ought to be unique: "id"`)
+
+	_, err = schema.Record("User").Field("id", schema.Long(), schema.WithOrder("up")).JSON()
+	ensureError(t, err, `record "User" field "id" order ought to be one of`)
+
+	// the first error is retained
+	_, err = schema.Record("User").Field("", schema.Long()).Field("id", schema.Long()).Codec()
+	ensureError(t, err, `record "User" field "" schema name ought to be non-empty string`)
+
+	_, err = schema.Enum("Status").JSON()
+	ensureError(t, err, `enum "Status" ought to have one or more symbols`)
+
+	_, err = schema.Enum("Status", "ACTIVE", "ACTIVE").JSON()
+	ensureError(t, err, `enum "Status" symbol ought to be unique: "ACTIVE"`)
+
+	_, err = schema.Enum("Status", "ACTIVE", "DISABLED").Default("DELETED").JSON()
+	ensureError(t, err, `enum "Status" default ought to be member of symbols`)
+
+	_, err = schema.Fixed("MD5", 0).JSON()
+	ensureError(t, err, `fixed "MD5" size ought to be greater than zero: 0`)
+
+	_, err = schema.JSON(schema.Union())
+	ensureError(t, err, "union ought to have one or more members")
+
+	_, err = schema.JSON(schema.Union(schema.Int(), schema.Optional(schema.Long())))
+	ensureError(t, err, "union member 2 ought not be union")
+
+	_, err = schema.Record("com.acme.Pair").
+		Field("a", schema.Fixed("Hash", 16)).
+		Field("b", schema.Fixed("Hash", 32)).
+		JSON()
+	ensureError(t, err, `type "com.acme.Hash" ought to be defined once`)
+}
+
+func TestBuilderDefaultErrors(t *testing.T) {
+	_, err := schema.Record("User").Field("id", schema.Long(), schema.WithDefault("abc")).JSON()
+	ensureError(t, err, `Record "User" field "id": default value ought to encode using field schema`)
+
+	// defaults of unions are values of their first member
+	_, err = schema.Record("User").Field("email", schema.Optional(schema.String()), schema.WithDefault("x")).JSON()
+	ensureError(t, err, `Record "User" field "email": default value ought to encode using field schema`)
+
+	_, err = schema.Record("User").Field("status", schema.Enum("Status", "ACTIVE"), schema.WithDefault("DELETED")).JSON()
+	ensureError(t, err, `Record "User" field "status": default value ought to encode using field schema`)
+
+	// types may use any name
+	_, err = schema.Record("User").Field("f", schema.Record("fieldDefault").Field("a", schema.Int()), schema.WithDefault(map[string]interface{}{"a": 1})).JSON()
+	if err != nil {
+		t.Error(err)
+	}
+
+	// defaults of types referring to types defined elsewhere are checked when
+	// the Codec is created
+	_, err = schema.Record("User").Field("friend", schema.Ref("Friend"), schema.WithDefault(13)).Codec()
+	ensureError(t, err, `unknown type name: "Friend"`)
+}
@@ -2,6 +2,7 @@ package goavro_test
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/karrick/goavro"
@@ -188,3 +189,253 @@ func TestMapValueTypeRecord(t *testing.T) {
 	// are returned as a Go map
 	testBinaryEncodePass(t, schema, datum, expected)
 }
+
+func TestSchemaTreeRecord(t *testing.T) {
+	codec, err := goavro.NewCodec(`
+{"type": "record", "name": "test.Weather", "doc": "A weather reading.", "aliases": ["Reading"],
+ "fields": [
+     {"name": "station", "type": "string", "order": "ignore", "doc": "station identifier"},
+     {"name": "time", "type": "long", "order": "descending"},
+     {"name": "temp", "type": ["null", "int"], "default": null},
+     {"name": "tags", "type": {"type": "map", "values": {"type": "array", "items": "string"}}, "aliases": ["labels"]}
+ ]
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, ok := codec.SchemaTree().(*goavro.RecordSchema)
+	if !ok {
+		t.Fatalf("Actual: %T; Expected: %T", codec.SchemaTree(), record)
+	}
+	if actual, expected := record.Type(), "record"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := record.Name(), "test.Weather"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := record.Namespace(), "test"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := record.Doc(), "A weather reading."; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := fmt.Sprintf("%v", record.Aliases()), "[Reading]"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	fields := record.Fields()
+	if actual, expected := len(fields), 4; actual != expected {
+		t.Fatalf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	for i, expected := range []struct{ name, typeName, order, doc string }{
+		{"station", "string", goavro.OrderIgnore, "station identifier"},
+		{"time", "long", goavro.OrderDescending, ""},
+		{"temp", "union", goavro.OrderAscending, ""},
+		{"tags", "map", goavro.OrderAscending, ""},
+	} {
+		field := fields[i]
+		if actual := field.Name(); actual != expected.name {
+			t.Errorf("Actual: %#v; Expected: %#v", actual, expected.name)
+		}
+		if actual := field.Schema().Type(); actual != expected.typeName {
+			t.Errorf("Actual: %#v; Expected: %#v", actual, expected.typeName)
+		}
+		if actual := field.Order(); actual != expected.order {
+			t.Errorf("Actual: %#v; Expected: %#v", actual, expected.order)
+		}
+		if actual := field.Doc(); actual != expected.doc {
+			t.Errorf("Actual: %#v; Expected: %#v", actual, expected.doc)
+		}
+	}
+
+	if _, ok := fields[1].Default(); ok {
+		t.Errorf("Actual: %#v; Expected: %#v", ok, false)
+	}
+	if value, ok := fields[2].Default(); !ok || value != nil {
+		t.Errorf("Actual: %#v, %#v; Expected: %#v, %#v", value, ok, nil, true)
+	}
+
+	members := fields[2].Schema().(*goavro.UnionSchema).Members()
+	if actual, expected := len(members), 2; actual != expected {
+		t.Fatalf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := members[0].Type()+","+members[1].Type(), "null,int"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	values := fields[3].Schema().(*goavro.MapSchema).Values()
+	if actual, expected := values.(*goavro.ArraySchema).Items().Type(), "string"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := fmt.Sprintf("%v", fields[3].Aliases()), "[labels]"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	if _, ok := record.Field("humidity"); ok {
+		t.Errorf("Actual: %#v; Expected: %#v", ok, false)
+	}
+	if field, ok := record.Field("time"); !ok || field != fields[1] {
+		t.Errorf("Actual: %#v; Expected: %#v", field, fields[1])
+	}
+}
+
+func TestSchemaTreeRecursive(t *testing.T) {
+	schema, err := goavro.ParseSchema(`{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := schema.(*goavro.RecordSchema)
+	field, _ := record.Field("next")
+	members := field.Schema().(*goavro.UnionSchema).Members()
+	if actual, expected := members[1], goavro.Schema(record); actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
+
+func TestSchemaTreeEnumAndFixed(t *testing.T) {
+	schema, err := goavro.ParseSchema(`[{"type":"enum","name":"e1","namespace":"com.example","symbols":["alpha","bravo"],"doc":"greek"},{"type":"fixed","name":"md5","size":16}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	members := schema.(*goavro.UnionSchema).Members()
+
+	enum := members[0].(*goavro.EnumSchema)
+	if actual, expected := enum.Name(), "com.example.e1"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := enum.Doc(), "greek"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := fmt.Sprintf("%v", enum.Symbols()), "[alpha bravo]"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	fixed := members[1].(*goavro.FixedSchema)
+	if actual, expected := fixed.Name(), "md5"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := fixed.Namespace(), ""; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := fixed.Size(), 16; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
+
+func TestSchemaTreeImmutable(t *testing.T) {
+	schema, err := goavro.ParseSchema(`{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"map","values":"int"},"default":{"a":1}}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := schema.(*goavro.RecordSchema)
+	record.Fields()[0] = nil
+	field, ok := record.Field("f1")
+	if !ok || field == nil {
+		t.Fatalf("Actual: %#v; Expected: %#v", field, "non-nil field")
+	}
+	value, _ := field.Default()
+	value.(map[string]interface{})["a"] = 2
+	value, _ = field.Default()
+	if actual, expected := fmt.Sprintf("%v", value), "map[a:1]"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
+
+func TestSchemaTreeInvalidAttributes(t *testing.T) {
+	testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"int","order":"sideways"}]}`, `Record "r1" field "f1" order ought to be one of`)
+}
+
+func TestSchemaTreeIgnoresInvalidAliases(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r1","aliases":["r0",3],"fields":[{"name":"f1","type":"int","aliases":"f2"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := codec.SchemaTree().(*goavro.RecordSchema)
+	if actual, expected := record.Aliases(), []string{"r0"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	field, _ := record.Field("f1")
+	if actual := field.Aliases(); len(actual) != 0 {
+		t.Errorf("Actual: %#v; Expected: none", actual)
+	}
+}
+
+func TestSchemaTreePrimitive(t *testing.T) {
+	for _, primitive := range []string{"boolean", "bytes", "double", "float", "int", "long", "null", "string"} {
+		schema, err := goavro.ParseSchema(primitive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := schema.Type(), primitive; actual != expected {
+			t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+		}
+		if _, ok := schema.(*goavro.PrimitiveSchema); !ok {
+			t.Errorf("Actual: %T; Expected: %T", schema, &goavro.PrimitiveSchema{})
+		}
+	}
+}
+
+func TestSchemaTreeProperties(t *testing.T) {
+	codec, err := goavro.NewCodec(`
+{"type": "record", "name": "User", "namespace": "com.acme", "sensitivity": "high",
+ "fields": [
+     {"name": "id", "type": "long"},
+     {"name": "email", "type": "string", "pii": true, "doc": "primary email", "tags": ["contact"]},
+     {"name": "token", "type": {"type": "fixed", "name": "Token", "size": 4, "pii": true}}
+ ]
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	props, err := codec.TypeProperties("com.acme.User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", props), "map[sensitivity:high]"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	props, err = codec.FieldProperties("com.acme.User", "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", props), "map[pii:true tags:[contact]]"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	props["tags"].([]interface{})[0] = "changed"
+
+	props, err = codec.FieldProperties("com.acme.User", "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := len(props), 0; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	props, err = codec.TypeProperties("com.acme.Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", props), "map[pii:true]"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	field, _ := codec.SchemaTree().(*goavro.RecordSchema).Field("email")
+	if value, ok := field.Property("tags"); !ok || fmt.Sprintf("%v", value) != "[contact]" {
+		t.Errorf("Actual: %#v, %#v; Expected: %#v, %#v", value, ok, []interface{}{"contact"}, true)
+	}
+	if value, ok := field.Property("missing"); ok || value != nil {
+		t.Errorf("Actual: %#v, %#v; Expected: %#v, %#v", value, ok, nil, false)
+	}
+	if actual, expected := field.Record().Name(), "com.acme.User"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	_, err = codec.TypeProperties("com.acme.Missing")
+	ensureError(t, err, `cannot find named type: "com.acme.Missing"`)
+	_, err = codec.FieldProperties("com.acme.Token", "email")
+	ensureError(t, err, `cannot find record: "com.acme.Token" is fixed`)
+	_, err = codec.FieldProperties("com.acme.User", "phone")
+	ensureError(t, err, `cannot find record "com.acme.User" field: "phone"`)
+}
@@ -0,0 +1,229 @@
+package goavro
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// defaultStreamBlockCount is the number of items of each array or map block
+// encoded from a producer, unless the WithMaxBlockCount or WithSizedBlocks
+// options specify a count.
+const defaultStreamBlockCount = 1024
+
+// ItemIterator produces the items of an array one at a time. The binary
+// encoders of arrays accept an ItemIterator in place of a slice, along with a
+// function of the same signature as Next, and a channel of type chan
+// interface{} or <-chan interface{}, which produces items until it is closed.
+type ItemIterator interface {
+	// Next returns the next item and true, or false when no items remain.
+	Next() (interface{}, bool, error)
+}
+
+// EntryIterator produces the entries of a map one at a time. The binary
+// encoders of maps accept an EntryIterator in place of a map, along with a
+// function of the same signature as Next, and a channel of type chan MapEntry
+// or <-chan MapEntry, which produces entries until it is closed.
+type EntryIterator interface {
+	// Next returns the key and the value of the next entry and true, or false
+	// when no entries remain.
+	Next() (string, interface{}, bool, error)
+}
+
+// MapEntry is an entry of a map produced by a channel.
+type MapEntry struct {
+	Key   string
+	Value interface{}
+}
+
+// itemProducer returns a function producing the items of datum, and true,
+// when datum is an ItemIterator, an iterator function, or a channel of items.
+func itemProducer(datum interface{}) (func() (interface{}, bool, error), bool) {
+	switch v := datum.(type) {
+	case ItemIterator:
+		return v.Next, true
+	case func() (interface{}, bool, error):
+		return v, true
+	case <-chan interface{}:
+		return func() (interface{}, bool, error) {
+			item, ok := <-v
+			return item, ok, nil
+		}, true
+	case chan interface{}:
+		return itemProducer((<-chan interface{})(v))
+	}
+	return nil, false
+}
+
+// entryProducer returns a function producing the entries of datum, and true,
+// when datum is an EntryIterator, an iterator function, or a channel of
+// entries.
+func entryProducer(datum interface{}) (func() (string, interface{}, bool, error), bool) {
+	switch v := datum.(type) {
+	case EntryIterator:
+		return v.Next, true
+	case func() (string, interface{}, bool, error):
+		return v, true
+	case <-chan MapEntry:
+		return func() (string, interface{}, bool, error) {
+			entry, ok := <-v
+			return entry.Key, entry.Value, ok, nil
+		}, true
+	case chan MapEntry:
+		return entryProducer((<-chan MapEntry)(v))
+	}
+	return nil, false
+}
+
+// appendProducedItems appends the binary encoding of an array whose items are
+// produced by next, in blocks of up to config.streamBlockCount() items. When
+// w is not nil, buf is written to w after each block, and truncated.
+func appendProducedItems(w io.Writer, buf []byte, itemCodec *Codec, config *codecConfig, next func() (interface{}, bool, error)) ([]byte, error) {
+	var i int
+	return appendProducedBlocks(w, buf, config, func(buf []byte) ([]byte, bool, error) {
+		item, ok, err := next()
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot encode binary array item %d: %s", i+1, err)
+		}
+		if !ok {
+			return buf, false, nil
+		}
+		if buf, err = itemCodec.binaryFromNative(buf, item); err != nil {
+			return nil, false, asEncodeError(err, itemCodec, item).within(strconv.Itoa(i)).prefixed("cannot encode binary array item %d: %v", i+1, item)
+		}
+		i++
+		return buf, true, nil
+	})
+}
+
+// appendProducedEntries appends the binary encoding of a map whose entries
+// are produced by next, in blocks of up to config.streamBlockCount() entries.
+// When w is not nil, buf is written to w after each block, and truncated.
+func appendProducedEntries(w io.Writer, buf []byte, valueCodec *Codec, config *codecConfig, next func() (string, interface{}, bool, error)) ([]byte, error) {
+	return appendProducedBlocks(w, buf, config, func(buf []byte) ([]byte, bool, error) {
+		k, v, ok, err := next()
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot encode binary map: %s", err)
+		}
+		if !ok {
+			return buf, false, nil
+		}
+		// only fails when given non string, so elide error checking
+		buf, _ = stringBinaryFromNative(buf, k)
+		if buf, err = valueCodec.binaryFromNative(buf, v); err != nil {
+			return nil, false, asEncodeError(err, valueCodec, v).within(k).prefixed("cannot encode binary map value for key %q: %v", k, v)
+		}
+		return buf, true, nil
+	})
+}
+
+// appendProducedBlocks appends blocks of items encoded by appendNext, which
+// returns false when no items remain, followed by the trailing 0 block count.
+// Because the number of items is not known in advance, the header of each
+// block is inserted once its items are encoded.
+func appendProducedBlocks(w io.Writer, buf []byte, config *codecConfig, appendNext func([]byte) ([]byte, bool, error)) ([]byte, error) {
+	maxBlockCount := config.streamBlockCount()
+	sized := config.sizedBlockCount > 0
+	for {
+		start := len(buf)
+		var blockCount int64
+		for blockCount < maxBlockCount {
+			var more bool
+			var err error
+			if buf, more, err = appendNext(buf); err != nil {
+				return nil, err
+			}
+			if !more {
+				break
+			}
+			blockCount++
+		}
+		if blockCount > 0 {
+			buf = insertBlockHeader(buf, start, blockCount, sized)
+		}
+		if blockCount < maxBlockCount {
+			return append(buf, 0), nil // append trailing 0 block count to signal end of collection
+		}
+		if w != nil {
+			if _, err := w.Write(buf); err != nil {
+				return nil, err
+			}
+			buf = buf[:0]
+		}
+	}
+}
+
+// WriteBinary writes the binary encoding of datum to w. Unlike
+// BinaryFromNative, arrays and maps provided by a producer, such as an
+// ItemIterator, an EntryIterator, or a channel, are written to w one block at
+// a time, so a very large datum may be encoded while holding only one block of
+// it in memory, provided its producers are the datum itself, or are held by
+// record fields or union values along the way to it. Producers nested within
+// items of other arrays or maps are encoded in memory.
+//
+// When encoding fails, WriteBinary returns without receiving the remaining
+// items of a channel, so a goroutine sending to it ought to stop once
+// WriteBinary returns, rather than block forever.
+//
+//     items := make(chan interface{})
+//     done := make(chan struct{})
+//     go func() {
+//         defer close(items)
+//         for i := 0; i < 1000000; i++ {
+//             select {
+//             case items <- i:
+//             case <-done:
+//                 return
+//             }
+//         }
+//     }()
+//     err := codec.WriteBinary(w, map[string]interface{}{"values": items})
+//     close(done) // stops the goroutine when WriteBinary failed
+func (c *Codec) WriteBinary(w io.Writer, datum interface{}) error {
+	var buf []byte
+	var err error
+	if c.node != nil && c.node.codec() == c {
+		buf, err = writeBinary(w, nil, c.node, datum)
+	} else {
+		// NOTE: Codecs such as projection codecs encode differently than the
+		// schema tree they describe.
+		buf, err = c.binaryFromNative(nil, datum)
+	}
+	if err != nil {
+		return asEncodeError(err, c, datum)
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// writeBinary appends the binary encoding of datum, described by schema s, to
+// buf, and writes the blocks of arrays and maps provided by a producer to w,
+// along with the bytes preceding them.
+func writeBinary(w io.Writer, buf []byte, s Schema, datum interface{}) ([]byte, error) {
+	switch v := s.(type) {
+	case *ArraySchema:
+		if next, ok := itemProducer(datum); ok {
+			return appendProducedItems(w, buf, v.items.codec(), v.c.config, next)
+		}
+	case *MapSchema:
+		if next, ok := entryProducer(datum); ok {
+			return appendProducedEntries(w, buf, v.values.codec(), v.c.config, next)
+		}
+	case *UnionSchema:
+		index, value, err := v.memberOf(datum)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode binary union: %s", err)
+		}
+		buf = appendLong(buf, int64(index))
+		member := v.members[index]
+		if buf, err = writeBinary(w, buf, member, value); err != nil {
+			return nil, asEncodeError(err, member.codec(), value).within(member.codec().typeName.fullName)
+		}
+		return buf, nil
+	case *RecordSchema:
+		return v.appendFields(buf, datum, func(buf []byte, fieldCodec *Codec, value interface{}) ([]byte, error) {
+			return writeBinary(w, buf, fieldCodec.node, value)
+		})
+	}
+	return s.codec().binaryFromNative(buf, datum)
+}
@@ -0,0 +1,224 @@
+package goavro_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+type testCountingIterator struct {
+	count, limit int
+}
+
+func (it *testCountingIterator) Next() (interface{}, bool, error) {
+	if it.count == it.limit {
+		return nil, false, nil
+	}
+	it.count++
+	return it.count, true, nil
+}
+
+type testEntryIterator struct {
+	keys []string
+}
+
+func (it *testEntryIterator) Next() (string, interface{}, bool, error) {
+	if len(it.keys) == 0 {
+		return "", nil, false, nil
+	}
+	key := it.keys[0]
+	it.keys = it.keys[1:]
+	return key, len(key), true, nil
+}
+
+// testWriter records the number of calls to Write.
+type testWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestStreamArrayProducers(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"array","items":"long"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := codec.BinaryFromNative(nil, []interface{}{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := make(chan interface{}, 3)
+	items <- 1
+	items <- 2
+	items <- 3
+	close(items)
+
+	var i int
+	next := func() (interface{}, bool, error) {
+		i++
+		return i, i <= 3, nil
+	}
+
+	for _, producer := range []interface{}{items, next, &testCountingIterator{limit: 3}} {
+		buf, err := codec.BinaryFromNative(nil, producer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf, expected) {
+			t.Errorf("producer: %T; Actual: %#v; Expected: %#v", producer, buf, expected)
+		}
+	}
+
+	if violations := codec.Validate(&testCountingIterator{limit: 3}); violations != nil {
+		t.Errorf("Actual: %v; Expected: %v", violations, nil)
+	}
+
+	// empty producers encode empty arrays
+	buf, err := codec.BinaryFromNative(nil, &testCountingIterator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{0}; !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", buf, expected)
+	}
+}
+
+func TestStreamMapProducers(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"map","values":"int"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{4, 2, 'a', 2, 4, 'b', 'c', 4, 0}
+
+	entries := make(chan goavro.MapEntry, 2)
+	entries <- goavro.MapEntry{Key: "a", Value: 1}
+	entries <- goavro.MapEntry{Key: "bc", Value: 2}
+	close(entries)
+
+	for _, producer := range []interface{}{entries, &testEntryIterator{keys: []string{"a", "bc"}}} {
+		buf, err := codec.BinaryFromNative(nil, producer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf, expected) {
+			t.Errorf("producer: %T; Actual: %#v; Expected: %#v", producer, buf, expected)
+		}
+	}
+}
+
+func TestStreamSizedBlocks(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"array","items":"int"}`, goavro.WithSizedBlocks(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, &testCountingIterator{limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := codec.BinaryFromNative(nil, []int{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", buf, expected)
+	}
+}
+
+func TestWriteBinary(t *testing.T) {
+	codec, err := goavro.NewCodecWithOptions(`{"type":"record","name":"r1","fields":[
+  {"name":"id","type":"string"},
+  {"name":"values","type":["null",{"type":"array","items":"long"}]},
+  {"name":"tail","type":"int","default":7}
+]}`, goavro.WithMaxBlockCount(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const count = 1050
+
+	w := new(testWriter)
+	err = codec.WriteBinary(w, map[string]interface{}{
+		"id":     "abc",
+		"values": goavro.Union("array", &testCountingIterator{limit: count}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// one write per full block, then one for the rest of the datum
+	if actual, expected := w.writes, count/100+1; actual != expected {
+		t.Errorf("Actual: %d; Expected: %d", actual, expected)
+	}
+
+	values := make([]interface{}, count)
+	for i := range values {
+		values[i] = int64(i + 1)
+	}
+	expected := map[string]interface{}{
+		"id":     "abc",
+		"values": goavro.Union("array", values),
+		"tail":   int32(7),
+	}
+	decoded, rest, err := codec.NativeFromBinary(w.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("Actual: %d bytes remaining; Expected: 0", len(rest))
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Actual: %v; Expected: %v", decoded, expected)
+	}
+
+	// data without producers are written as BinaryFromNative encodes them
+	w = new(testWriter)
+	if err = codec.WriteBinary(w, expected); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(w.Bytes(), buf) {
+		t.Errorf("Actual: %#v; Expected: %#v", w.Bytes(), buf)
+	}
+}
+
+func TestWriteBinaryErrors(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r1","fields":[{"name":"values","type":{"type":"array","items":"int"}}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := func() (interface{}, bool, error) {
+		return nil, false, errors.New("producer failed")
+	}
+	err = codec.WriteBinary(new(bytes.Buffer), map[string]interface{}{"values": next})
+	ensureError(t, err, `cannot encode binary record "r1" field "values": value does not match its schema: cannot encode binary array item 1: producer failed`)
+
+	items := make(chan interface{}, 1)
+	items <- "abc"
+	close(items)
+	err = codec.WriteBinary(new(bytes.Buffer), map[string]interface{}{"values": items})
+	ensureError(t, err, "cannot encode binary array item 1: abc")
+	var encodeError *goavro.EncodeError
+	if !errors.As(err, &encodeError) || encodeError.Path != "/values/0" {
+		t.Errorf("Actual: %#v; Expected: path %q", err, "/values/0")
+	}
+
+	err = codec.WriteBinary(new(bytes.Buffer), map[string]interface{}{})
+	ensureError(t, err, `cannot encode binary record "r1" field "values": schema does not specify default value and no value provided`)
+
+	codec, err = goavro.NewCodecWithOptions(`{"type":"record","name":"r1","fields":[{"name":"values","type":{"type":"array","items":"int"}}]}`, goavro.WithStrictFields())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = codec.WriteBinary(new(bytes.Buffer), map[string]interface{}{"values": next, "extra": 1})
+	ensureError(t, err, `cannot encode binary record "r1": unknown fields: ["extra"]`)
+}
@@ -2,8 +2,12 @@ package goavro
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"time"
 )
 
 // Union wraps a datum value in a map for encoding as a Union, as required by
@@ -15,7 +19,52 @@ func Union(name string, datum interface{}) interface{} {
 	return map[string]interface{}{name: datum}
 }
 
-func buildCodecForTypeDescribedBySlice(st map[string]*Codec, enclosingNamespace string, schemaArray []interface{}) (*Codec, error) {
+// UnionValue is the decoded value of a union, returned in place of a map
+// wrapping the value when the Codec was created with the WithUnionValues
+// option. Union encoders also accept a UnionValue, whose Name selects the
+// member encoding Value.
+type UnionValue struct {
+	Index int         // index of the member within the union
+	Name  string      // full name of the member type, such as "string" or "com.example.Address"
+	Value interface{} // decoded value of the member
+}
+
+// Representations of decoded non-nil union values.
+const (
+	unionAsMap   = iota // map[string]interface{} with a single key naming the member
+	unionAsBare         // the value itself
+	unionAsValue        // UnionValue
+)
+
+// unionRepresentation returns how the decoders of a union with the specified
+// members represent non-nil values, as configured by the
+// WithBareNullableUnions and WithUnionValues options.
+func unionRepresentation(config *codecConfig, allowedTypes []string) int {
+	if config == nil {
+		return unionAsMap
+	}
+	if config.bareNullableUnions && len(allowedTypes) == 2 && (allowedTypes[0] == "null" || allowedTypes[1] == "null") {
+		return unionAsBare
+	}
+	if config.unionValues {
+		return unionAsValue
+	}
+	return unionAsMap
+}
+
+// wrapUnionValue returns the decoded non-nil value of the member at index,
+// represented as specified.
+func wrapUnionValue(representation int, allowedTypes []string, index int, value interface{}) interface{} {
+	switch representation {
+	case unionAsBare:
+		return value
+	case unionAsValue:
+		return UnionValue{Index: index, Name: allowedTypes[index], Value: value}
+	}
+	return Union(allowedTypes[index], value)
+}
+
+func buildCodecForTypeDescribedBySlice(st *symbolTable, enclosingNamespace string, schemaArray []interface{}) (*Codec, error) {
 	if len(schemaArray) == 0 {
 		return nil, errors.New("Union ought to have one or more members")
 	}
@@ -40,60 +89,149 @@ func buildCodecForTypeDescribedBySlice(st map[string]*Codec, enclosingNamespace
 		indexFromName[fullName] = i
 	}
 
-	return &Codec{
+	// memberOf returns the index of the member that encodes datum, along with
+	// the value that member encodes. Unless the codec infers members, or
+	// decodes bare values, non-nil values ought to be wrapped in a map whose
+	// single key names the member.
+	//
+	// NOTE: When the union decodes bare values, and its non-null member is a
+	// record or a map, a map datum is always the bare value of that member, even
+	// when its single key names the member, so decoded values may be encoded
+	// again. Such values may be wrapped in a UnionValue instead.
+	inferMembers := st.config.unionInference
+	representation := unionRepresentation(st.config, allowedTypes)
+	bareIndex := 0
+	if allowedTypes[0] == "null" {
+		bareIndex = 1
+	}
+	memberOf := func(datum interface{}) (int, interface{}, error) {
+		if representation == unionAsBare {
+			if _, ok := datum.(map[string]interface{}); ok {
+				switch codecFromIndex[bareIndex].node.(type) {
+				case *RecordSchema, *MapSchema:
+					return bareIndex, datum, nil
+				}
+			}
+		}
+		switch v := datum.(type) {
+		case nil:
+			index, ok := indexFromName["null"]
+			if !ok {
+				return 0, nil, fmt.Errorf("no member schema types support datum: allowed types: %v; received: %T", allowedTypes, datum)
+			}
+			return index, nil, nil
+		case UnionValue:
+			index, ok := indexFromName[v.Name]
+			if !ok {
+				return 0, nil, fmt.Errorf("no member schema types support datum: allowed types: %v; received: %q", allowedTypes, v.Name)
+			}
+			return index, v.Value, nil
+		case map[string]interface{}:
+			if len(v) == 1 {
+				// will execute exactly once
+				for key, value := range v {
+					if index, ok := indexFromName[key]; ok {
+						return index, value, nil
+					}
+					if !inferMembers {
+						return 0, nil, fmt.Errorf("no member schema types support datum: allowed types: %v; received: %T", allowedTypes, datum)
+					}
+				}
+			}
+		}
+		if representation == unionAsBare {
+			return bareIndex, datum, nil
+		}
+		if !inferMembers {
+			return 0, nil, fmt.Errorf("non-nil Union values ought to be specified with Go map[string]interface{}, with single key equal to type name, and value equal to datum value: %v; received: %T", allowedTypes, datum)
+		}
+		index, err := inferUnionMember(codecFromIndex, indexFromName, datum)
+		if err != nil {
+			return 0, nil, fmt.Errorf("%s; allowed types: %v", err, allowedTypes)
+		}
+		return index, datum, nil
+	}
+
+	var c *Codec
+	c = &Codec{
 		// NOTE: To support record field default values, union schema set to the
 		// type name of first member
 		schema: codecFromIndex[0].typeName.short(),
 
 		typeName: &name{"union", nullNamespace},
-		nativeFromBinary: func(buf []byte) (interface{}, []byte, error) {
+		config:   st.config,
+		binaryInto: func(buf []byte, dst interface{}) (interface{}, []byte, error) {
 			var decoded interface{}
-			var err error
-
-			decoded, buf, err = longNativeFromBinary(buf)
+			index, buf, err := longFromBinary(buf)
 			if err != nil {
 				return nil, nil, err
 			}
-			index := decoded.(int64) // longDecoder always returns int64, so elide error checking
 			if index < 0 || index >= int64(len(codecFromIndex)) {
 				return nil, nil, fmt.Errorf("cannot decode binary union: index ought to be between 0 and %d; read index: %d", len(codecFromIndex)-1, index)
 			}
 			c := codecFromIndex[index]
-			decoded, buf, err = c.nativeFromBinary(buf)
+			name := allowedTypes[index]
+			// NOTE: Reuse the previous value only when it holds a value of the
+			// same member, and reuse the map wrapping it.
+			var previous map[string]interface{}
+			var previousValue interface{}
+			switch representation {
+			case unionAsBare:
+				previousValue = dst
+			case unionAsValue:
+				if v, ok := dst.(UnionValue); ok && v.Index == int(index) {
+					previousValue = v.Value
+				}
+			default:
+				if previous, _ = dst.(map[string]interface{}); len(previous) == 1 {
+					previousValue = previous[name]
+				}
+			}
+			decoded, buf, err = c.nativeFromBinaryInto(buf, previousValue)
 			if err != nil {
-				return nil, nil, fmt.Errorf("cannot decode binary union item %d: %s", index+1, err)
+				return nil, nil, asDecodeError(err, c).within(name).prefixed("cannot decode binary union item %d", index+1)
 			}
 			if decoded == nil {
-				// do not wrap a nil value in a map
+				// do not wrap a nil value
 				return nil, buf, nil
 			}
-			// Non-nil values are wrapped in a map with single key set to type name of value
-			return Union(allowedTypes[index], decoded), buf, nil
+			if previousValue != nil && previous != nil {
+				previous[name] = decoded
+				return previous, buf, nil
+			}
+			// Non-nil values are wrapped in a map with single key set to type name of value,
+			// unless the codec was configured otherwise
+			return wrapUnionValue(representation, allowedTypes, int(index), decoded), buf, nil
+		},
+		nativeFromBinary: func(buf []byte) (interface{}, []byte, error) {
+			return c.binaryInto(buf, nil)
+		},
+		skipBinary: func(buf []byte) ([]byte, error) {
+			index, buf, err := longFromBinary(buf)
+			if err != nil {
+				return nil, fmt.Errorf("cannot skip binary union: %w", err)
+			}
+			if index < 0 || index >= int64(len(codecFromIndex)) {
+				return nil, fmt.Errorf("cannot skip binary union: index ought to be between 0 and %d; read index: %d", len(codecFromIndex)-1, index)
+			}
+			c := codecFromIndex[index]
+			if buf, err = c.skipBinary(buf); err != nil {
+				return nil, asDecodeError(err, c).within(allowedTypes[index]).prefixed("cannot skip binary union item %d", index+1)
+			}
+			return buf, nil
 		},
 		binaryFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
-			switch v := datum.(type) {
-			case nil:
-				index, ok := indexFromName["null"]
-				if !ok {
-					return nil, fmt.Errorf("cannot encode binary union: no member schema types support datum: allowed types: %v; received: %T", allowedTypes, datum)
-				}
-				return longBinaryFromNative(buf, index)
-			case map[string]interface{}:
-				if len(v) != 1 {
-					return nil, fmt.Errorf("cannot encode binary union: non-nil Union values ought to be specified with Go map[string]interface{}, with single key equal to type name, and value equal to datum value: %v; received: %T", allowedTypes, datum)
-				}
-				// will execute exactly once
-				for key, value := range v {
-					index, ok := indexFromName[key]
-					if !ok {
-						return nil, fmt.Errorf("cannot encode binary union: no member schema types support datum: allowed types: %v; received: %T", allowedTypes, datum)
-					}
-					c := codecFromIndex[index]
-					buf, _ = longBinaryFromNative(buf, index)
-					return c.binaryFromNative(buf, value)
-				}
+			index, value, err := memberOf(datum)
+			if err != nil {
+				return nil, fmt.Errorf("cannot encode binary union: %s", err)
 			}
-			return nil, fmt.Errorf("cannot encode binary union: non-nil Union values ought to be specified with Go map[string]interface{}, with single key equal to type name, and value equal to datum value: %v; received: %T", allowedTypes, datum)
+			buf = appendLong(buf, int64(index))
+			c := codecFromIndex[index]
+			encoded, err := c.binaryFromNative(buf, value)
+			if err != nil {
+				return nil, asEncodeError(err, c, value).within(allowedTypes[index])
+			}
+			return encoded, nil
 		},
 		nativeFromTextual: func(buf []byte) (interface{}, []byte, error) {
 			if len(buf) >= 4 && bytes.Equal(buf[:4], []byte("null")) {
@@ -106,45 +244,178 @@ func buildCodecForTypeDescribedBySlice(st map[string]*Codec, enclosingNamespace
 			var err error
 			datum, buf, err = genericMapTextDecoder(buf, nil, codecFromName)
 			if err != nil {
-				return nil, nil, fmt.Errorf("cannot decode textual union: %s", err)
+				return nil, nil, asDecodeError(err, c).prefixed("cannot decode textual union")
+			}
+			if representation != unionAsMap {
+				for name, value := range datum.(map[string]interface{}) {
+					return wrapUnionValue(representation, allowedTypes, indexFromName[name], value), buf, nil
+				}
 			}
 
 			return datum, buf, nil
 		},
 		textualFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
-			switch v := datum.(type) {
-			case nil:
-				_, ok := indexFromName["null"]
-				if !ok {
-					return nil, fmt.Errorf("cannot encode textual union: no member schema types support datum: allowed types: %v; received: %T", allowedTypes, datum)
-				}
+			index, value, err := memberOf(datum)
+			if err != nil {
+				return nil, fmt.Errorf("cannot encode textual union: %s", err)
+			}
+			if datum == nil {
 				return append(buf, "null"...), nil
-			case map[string]interface{}:
-				if len(v) != 1 {
-					return nil, fmt.Errorf("cannot encode textual union: non-nil Union values ought to be specified with Go map[string]interface{}, with single key equal to type name, and value equal to datum value: %v; received: %T", allowedTypes, datum)
+			}
+			key := allowedTypes[index]
+			buf = append(buf, '{')
+			if buf, err = stringTextualFromNative(buf, key); err != nil {
+				return nil, fmt.Errorf("cannot encode textual union: %s", err)
+			}
+			buf = append(buf, ':')
+			c := codecFromIndex[index]
+			if buf, err = c.textualFromNative(buf, value); err != nil {
+				return nil, asEncodeError(err, c, value).within(key).prefixed("cannot encode textual union")
+			}
+			return append(buf, '}'), nil
+		},
+	}
+	members := make([]Schema, len(codecFromIndex))
+	for i, memberCodec := range codecFromIndex {
+		members[i] = memberCodec.node
+	}
+	c.node = &UnionSchema{c: c, members: members, memberOf: memberOf}
+	return c, nil
+}
+
+// Preference order of the numeric members of a union receiving a bare numeric
+// value, by the kind of the value. A value is encoded using the first member in
+// its order that the union has, and whose encoder accepts the value without
+// losing precision.
+var (
+	unionOrderForInt32   = []string{"int", "long", "double", "float"}
+	unionOrderForInt64   = []string{"long", "int", "double", "float"}
+	unionOrderForFloat32 = []string{"float", "double", "long", "int"}
+	unionOrderForFloat64 = []string{"double", "float", "long", "int"}
+)
+
+// inferUnionMember returns the index of the member of the union that ought to
+// encode datum, a value not wrapped in a map naming its member. It returns an
+// error when no member supports datum, or when more than one member supports
+// datum and no rule prefers one of them.
+//
+// Numeric values use the first member of their preference order whose encoder
+// accepts them. Strings use the string member, or else the only enum listing
+// them as a symbol. Byte slices use the bytes member, or else the only fixed of
+// their size. Slices use the array member. Maps use the only map or record
+// member for which they are valid. Values of time.Time use the only date or
+// timestamp member translating them, which requires the WithLogicalTypes option.
+func inferUnionMember(members []*Codec, indexFromName map[string]int, datum interface{}) (int, error) {
+	firstOf := func(names []string) (int, error) {
+		for _, name := range names {
+			if index, ok := indexFromName[name]; ok {
+				return index, nil
+			}
+		}
+		return 0, fmt.Errorf("no member schema types support datum: %T", datum)
+	}
+	firstAccepting := func(names []string) (int, error) {
+		var firstErr error
+		for _, name := range names {
+			index, ok := indexFromName[name]
+			if !ok {
+				continue
+			}
+			if _, err := members[index].binaryFromNative(nil, datum); err != nil {
+				if firstErr == nil {
+					firstErr = err
 				}
-				// will execute exactly once
-				for key, value := range v {
-					index, ok := indexFromName[key]
-					if !ok {
-						return nil, fmt.Errorf("cannot encode textual union: no member schema types support datum: allowed types: %v; received: %T", allowedTypes, datum)
-					}
-					buf = append(buf, '{')
-					var err error
-					buf, err = stringTextualFromNative(buf, key)
-					if err != nil {
-						return nil, fmt.Errorf("cannot encode textual union: %s", err)
-					}
-					buf = append(buf, ':')
-					c := codecFromIndex[index]
-					buf, err = c.textualFromNative(buf, value)
-					if err != nil {
-						return nil, fmt.Errorf("cannot encode textual union: %s", err)
+				continue
+			}
+			return index, nil
+		}
+		if firstErr != nil {
+			return 0, firstErr
+		}
+		return 0, fmt.Errorf("no member schema types support datum: %T", datum)
+	}
+	onlyOf := func(matches func(Schema) bool) (int, error) {
+		found := -1
+		var candidates []string
+		for i, member := range members {
+			if matches(member.node) {
+				found = i
+				candidates = append(candidates, member.typeName.fullName)
+			}
+		}
+		switch len(candidates) {
+		case 0:
+			return 0, fmt.Errorf("no member schema types support datum: %T", datum)
+		case 1:
+			return found, nil
+		}
+		return 0, fmt.Errorf("ambiguous datum: %T matches member schema types: %v", datum, candidates)
+	}
+
+	switch v := datum.(type) {
+	case time.Time:
+		return onlyOf(func(s Schema) bool {
+			p, ok := s.(*PrimitiveSchema)
+			return ok && (p.logicalType == "date" || p.logicalType == "timestamp-millis" || p.logicalType == "timestamp-micros")
+		})
+	case bool:
+		return firstOf([]string{"boolean"})
+	case string:
+		if index, ok := indexFromName["string"]; ok {
+			return index, nil
+		}
+		return onlyOf(func(s Schema) bool {
+			if enum, ok := s.(*EnumSchema); ok {
+				for _, symbol := range enum.symbols {
+					if symbol == v {
+						return true
 					}
-					return append(buf, '}'), nil
 				}
 			}
-			return nil, fmt.Errorf("cannot encode textual union: non-nil values ought to be specified with Go map[string]interface{}, with single key equal to type name, and value equal to datum value: %v; received: %T", allowedTypes, datum)
-		},
-	}, nil
+			return false
+		})
+	case []byte:
+		if index, ok := indexFromName["bytes"]; ok {
+			return index, nil
+		}
+		return onlyOf(func(s Schema) bool {
+			fixed, ok := s.(*FixedSchema)
+			return ok && fixed.size == uint(len(v))
+		})
+	case int8, int16, int32, uint8, uint16:
+		return firstAccepting(unionOrderForInt32)
+	case float32:
+		return firstAccepting(unionOrderForFloat32)
+	case float64:
+		return firstAccepting(unionOrderForFloat64)
+	case json.Number:
+		if _, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return firstAccepting(unionOrderForInt64)
+		}
+		return firstAccepting(unionOrderForFloat64)
+	case map[string]interface{}:
+		return onlyOf(func(s Schema) bool {
+			switch s.(type) {
+			case *MapSchema, *RecordSchema:
+				return len(validate(s, "", v, nil)) == 0
+			}
+			return false
+		})
+	}
+
+	switch reflect.ValueOf(datum).Kind() {
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return firstAccepting(unionOrderForInt64)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return firstAccepting(unionOrderForInt32)
+	case reflect.Float32:
+		return firstAccepting(unionOrderForFloat32)
+	case reflect.Float64:
+		return firstAccepting(unionOrderForFloat64)
+	case reflect.Slice, reflect.Array:
+		return firstOf([]string{"array"})
+	case reflect.Map:
+		return firstOf([]string{"map"})
+	}
+	return 0, fmt.Errorf("no member schema types support datum: %T", datum)
 }
@@ -2,17 +2,20 @@ package goavro_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"math"
+	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/karrick/goavro"
 )
 
 func TestSchemaUnion(t *testing.T) {
 	testSchemaInvalid(t, `[{"type":"enum","name":"e1","symbols":["alpha","bravo"]},"e1"]`, "Union item 2 ought to be unique type")
-	testSchemaInvalid(t, `[{"type":"enum","name":"com.example.one","symbols":["red","green","blue"]},{"type":"enum","name":"one","namespace":"com.example","symbols":["dog","cat"]}]`, "Union item 2 ought to be unique type")
+	testSchemaInvalid(t, `[{"type":"enum","name":"com.example.one","symbols":["red","green","blue"]},{"type":"enum","name":"one","namespace":"com.example","symbols":["dog","cat"]}]`, `Union item 2 ought to be valid Avro type: Enum ought to have valid name: schema ought not redefine type: "com.example.one"`)
 }
 
 func TestUnion(t *testing.T) {
@@ -213,3 +216,244 @@ func ExampleUnion3() {
 	fmt.Println(value)
 	// Output: decoded string: NaN
 }
+
+func testUnionInference(t *testing.T, schema string, datum interface{}, expectedBinary []byte, expectedText string) {
+	t.Helper()
+	codec, err := goavro.NewCodecWithOptions(schema, goavro.WithUnionInference())
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatalf("schema: %s; datum: %#v; %s", schema, datum, err)
+	}
+	if !bytes.Equal(buf, expectedBinary) {
+		t.Errorf("schema: %s; datum: %#v; Actual: %#v; Expected: %#v", schema, datum, buf, expectedBinary)
+	}
+	buf, err = codec.TextualFromNative(nil, datum)
+	if err != nil {
+		t.Fatalf("schema: %s; datum: %#v; %s", schema, datum, err)
+	}
+	if string(buf) != expectedText {
+		t.Errorf("schema: %s; datum: %#v; Actual: %s; Expected: %s", schema, datum, buf, expectedText)
+	}
+	if violations := codec.Validate(datum); violations != nil {
+		t.Errorf("schema: %s; datum: %#v; Actual: %v; Expected: %v", schema, datum, violations, nil)
+	}
+}
+
+type testUnionID int64
+
+func TestUnionInference(t *testing.T) {
+	testUnionInference(t, `["null","string"]`, nil, []byte{0}, `null`)
+	testUnionInference(t, `["null","string"]`, "abc", []byte{2, 6, 'a', 'b', 'c'}, `{"string":"abc"}`)
+	testUnionInference(t, `["null","string"]`, goavro.Union("string", "abc"), []byte{2, 6, 'a', 'b', 'c'}, `{"string":"abc"}`)
+	testUnionInference(t, `["null","boolean"]`, true, []byte{2, 1}, `{"boolean":true}`)
+	testUnionInference(t, `["null","bytes"]`, []byte("a"), []byte{2, 2, 'a'}, `{"bytes":"a"}`)
+
+	// numeric values prefer the member matching their Go type
+	testUnionInference(t, `["null","int","long","double"]`, int32(3), []byte{2, 6}, `{"int":3}`)
+	testUnionInference(t, `["null","int","long","double"]`, 3, []byte{4, 6}, `{"long":3}`)
+	testUnionInference(t, `["null","int","long","double"]`, testUnionID(3), []byte{4, 6}, `{"long":3}`)
+	testUnionInference(t, `["null","int","long","double"]`, 3.5, []byte{6, 0, 0, 0, 0, 0, 0, 0xc, 0x40}, `{"double":3.5}`)
+	// and otherwise the first member of their preference order
+	testUnionInference(t, `["null","long"]`, int32(3), []byte{2, 6}, `{"long":3}`)
+	testUnionInference(t, `["null","long"]`, float64(3), []byte{2, 6}, `{"long":3}`)
+	testUnionInference(t, `["null","long"]`, json.Number("3"), []byte{2, 6}, `{"long":3}`)
+	// and skip members that cannot encode the value without losing precision
+	testUnionInference(t, `["null","int","double"]`, int64(1)<<40, []byte{4, 0, 0, 0, 0, 0, 0, 0x70, 0x42}, `{"double":1099511627776}`)
+	testUnionInference(t, `["null","int","long"]`, float64(1<<40), []byte{4, 0x80, 0x80, 0x80, 0x80, 0x80, 0x40}, `{"long":1099511627776}`)
+
+	// enum and fixed members are inferred when only one of them fits
+	testUnionInference(t, `["null",{"type":"enum","name":"e1","symbols":["A","B"]},{"type":"enum","name":"e2","symbols":["C"]}]`, "C", []byte{4, 0}, `{"e2":"C"}`)
+	testUnionInference(t, `["null",{"type":"fixed","name":"f1","size":1},{"type":"fixed","name":"f2","size":2}]`, []byte("ab"), []byte{4, 'a', 'b'}, `{"f2":"ab"}`)
+
+	// records and maps are inferred when the value is only valid for one of them
+	testUnionInference(t, `["null",{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]},{"type":"record","name":"r2","fields":[{"name":"b","type":"int"}]}]`,
+		map[string]interface{}{"b": 1}, []byte{4, 2}, `{"r2":{"b":1}}`)
+	testUnionInference(t, `["null",{"type":"array","items":"int"}]`, []int{1}, []byte{2, 2, 2, 0}, `{"array":[1]}`)
+}
+
+func TestUnionInferenceTime(t *testing.T) {
+	schema := `["null","string",{"type":"long","logicalType":"timestamp-millis"}]`
+	codec, err := goavro.NewCodecWithOptions(schema, goavro.WithUnionInference(), goavro.WithLogicalTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, time.Unix(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{4, 0xd0, 0xf}; !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", buf, expected)
+	}
+
+	codec, err = goavro.NewCodecWithOptions(`["null",{"type":"int","logicalType":"date"},{"type":"long","logicalType":"timestamp-micros"}]`, goavro.WithUnionInference(), goavro.WithLogicalTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.BinaryFromNative(nil, time.Unix(1, 0))
+	ensureError(t, err, "ambiguous datum: time.Time matches member schema types: [int long]")
+}
+
+func TestUnionInferenceErrors(t *testing.T) {
+	codec, err := goavro.NewCodec(`["null","string"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.BinaryFromNative(nil, "abc")
+	ensureError(t, err, "cannot encode binary union: non-nil Union values ought to be specified with Go map[string]interface{}")
+
+	codec, err = goavro.NewCodecWithOptions(`["null",{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]},{"type":"record","name":"r2","fields":[{"name":"a","type":"long"}]}]`, goavro.WithUnionInference())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.BinaryFromNative(nil, map[string]interface{}{"a": 1})
+	ensureError(t, err, "cannot encode binary union: ambiguous datum: map[string]interface {} matches member schema types: [r1 r2]")
+	_, err = codec.TextualFromNative(nil, map[string]interface{}{"a": 1})
+	ensureError(t, err, "cannot encode textual union: ambiguous datum")
+	_, err = codec.BinaryFromNative(nil, map[string]interface{}{"b": 1})
+	ensureError(t, err, "no member schema types support datum")
+	_, err = codec.BinaryFromNative(nil, "abc")
+	ensureError(t, err, "no member schema types support datum: string; allowed types: [null r1 r2]")
+	if violations := codec.Validate("abc"); len(violations) != 1 {
+		t.Errorf("Actual: %v; Expected: 1 violation", violations)
+	}
+
+	// the inferred member still checks the value
+	codec, err = goavro.NewCodecWithOptions(`["null","int"]`, goavro.WithUnionInference())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.BinaryFromNative(nil, 3.5)
+	ensureError(t, err, "cannot encode binary int: provided Go float64 would lose precision")
+}
+
+func testUnionRepresentation(t *testing.T, schema string, options []goavro.CodecOption, datum interface{}, encoded []byte, text string) {
+	t.Helper()
+	codec, err := goavro.NewCodecWithOptions(schema, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _, err := codec.NativeFromBinary(encoded)
+	if err != nil {
+		t.Fatalf("schema: %s; %s", schema, err)
+	}
+	if !reflect.DeepEqual(decoded, datum) {
+		t.Errorf("schema: %s; Actual: %#v; Expected: %#v", schema, decoded, datum)
+	}
+	decoded, _, err = codec.NativeFromBinaryInto(encoded, decoded)
+	if err != nil {
+		t.Fatalf("schema: %s; %s", schema, err)
+	}
+	if !reflect.DeepEqual(decoded, datum) {
+		t.Errorf("schema: %s; Actual: %#v; Expected: %#v", schema, decoded, datum)
+	}
+	decoded, _, err = codec.NativeFromTextual([]byte(text))
+	if err != nil {
+		t.Fatalf("schema: %s; %s", schema, err)
+	}
+	if !reflect.DeepEqual(decoded, datum) {
+		t.Errorf("schema: %s; Actual: %#v; Expected: %#v", schema, decoded, datum)
+	}
+	buf, err := codec.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatalf("schema: %s; datum: %#v; %s", schema, datum, err)
+	}
+	if !bytes.Equal(buf, encoded) {
+		t.Errorf("schema: %s; datum: %#v; Actual: %#v; Expected: %#v", schema, datum, buf, encoded)
+	}
+	buf, err = codec.TextualFromNative(nil, datum)
+	if err != nil {
+		t.Fatalf("schema: %s; datum: %#v; %s", schema, datum, err)
+	}
+	if string(buf) != text {
+		t.Errorf("schema: %s; datum: %#v; Actual: %s; Expected: %s", schema, datum, buf, text)
+	}
+	if violations := codec.Validate(datum); violations != nil {
+		t.Errorf("schema: %s; datum: %#v; Actual: %v; Expected: %v", schema, datum, violations, nil)
+	}
+	w := new(bytes.Buffer)
+	if err = codec.WriteBinary(w, datum); err != nil {
+		t.Fatalf("schema: %s; datum: %#v; %s", schema, datum, err)
+	}
+	if !bytes.Equal(w.Bytes(), encoded) {
+		t.Errorf("schema: %s; datum: %#v; Actual: %#v; Expected: %#v", schema, datum, w.Bytes(), encoded)
+	}
+}
+
+func TestUnionBareNullable(t *testing.T) {
+	bare := []goavro.CodecOption{goavro.WithBareNullableUnions()}
+	testUnionRepresentation(t, `["null","string"]`, bare, "abc", []byte{2, 6, 'a', 'b', 'c'}, `{"string":"abc"}`)
+	testUnionRepresentation(t, `["string","null"]`, bare, "abc", []byte{0, 6, 'a', 'b', 'c'}, `{"string":"abc"}`)
+	testUnionRepresentation(t, `["null","string"]`, bare, nil, []byte{0}, `null`)
+	testUnionRepresentation(t, `["null",{"type":"array","items":"int"}]`, bare, []interface{}{int32(1)}, []byte{2, 2, 2, 0}, `{"array":[1]}`)
+	testUnionRepresentation(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","long"]}]}`, bare,
+		map[string]interface{}{"f1": int64(3)}, []byte{2, 6}, `{"f1":{"long":3}}`)
+
+	// records and maps with a single key are bare values, even when the key
+	// names the member
+	testUnionRepresentation(t, `["null",{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]}]`, bare,
+		map[string]interface{}{"a": int32(1)}, []byte{2, 2}, `{"r1":{"a":1}}`)
+	testUnionRepresentation(t, `["null",{"type":"record","name":"r1","fields":[{"name":"r1","type":"int"}]}]`, bare,
+		map[string]interface{}{"r1": int32(1)}, []byte{2, 2}, `{"r1":{"r1":1}}`)
+	testUnionRepresentation(t, `["null",{"type":"map","values":"int"}]`, bare,
+		map[string]interface{}{"map": int32(1)}, []byte{2, 2, 6, 'm', 'a', 'p', 2, 0}, `{"map":{"map":1}}`)
+	testBinaryEncodePass(t, `["null",{"type":"map","values":"int"}]`, goavro.UnionValue{Name: "map", Value: map[string]interface{}{"k": 1}}, []byte{2, 2, 2, 'k', 2, 0})
+
+	// other unions continue to be wrapped
+	testUnionRepresentation(t, `["null","int","string"]`, bare, goavro.Union("string", "abc"), []byte{4, 6, 'a', 'b', 'c'}, `{"string":"abc"}`)
+	testUnionRepresentation(t, `["int","string"]`, bare, goavro.Union("int", int32(3)), []byte{0, 6}, `{"int":3}`)
+
+	// wrapped values continue to be accepted
+	codec, err := goavro.NewCodecWithOptions(`["null","string"]`, bare...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, goavro.Union("string", "abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{2, 6, 'a', 'b', 'c'}; !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", buf, expected)
+	}
+	_, err = codec.BinaryFromNative(nil, 3)
+	ensureError(t, err, "expected: string; received: int")
+	if violations := codec.Validate(3); len(violations) != 1 {
+		t.Errorf("Actual: %v; Expected: 1 violation", violations)
+	}
+}
+
+func TestUnionValues(t *testing.T) {
+	values := []goavro.CodecOption{goavro.WithUnionValues()}
+	testUnionRepresentation(t, `["null","string"]`, values, goavro.UnionValue{Index: 1, Name: "string", Value: "abc"}, []byte{2, 6, 'a', 'b', 'c'}, `{"string":"abc"}`)
+	testUnionRepresentation(t, `["null","string"]`, values, nil, []byte{0}, `null`)
+	testUnionRepresentation(t, `["int",{"type":"record","name":"com.example.r1","fields":[{"name":"f1","type":"int"}]}]`, values,
+		goavro.UnionValue{Index: 1, Name: "com.example.r1", Value: map[string]interface{}{"f1": int32(3)}}, []byte{2, 6}, `{"com.example.r1":{"f1":3}}`)
+
+	// combined with bare nullable unions, only other unions return UnionValue
+	both := []goavro.CodecOption{goavro.WithBareNullableUnions(), goavro.WithUnionValues()}
+	testUnionRepresentation(t, `["null","string"]`, both, "abc", []byte{2, 6, 'a', 'b', 'c'}, `{"string":"abc"}`)
+	testUnionRepresentation(t, `["null","int","string"]`, both, goavro.UnionValue{Index: 2, Name: "string", Value: "abc"}, []byte{4, 6, 'a', 'b', 'c'}, `{"string":"abc"}`)
+
+	// encoders accept UnionValue without the option, and use its name
+	testBinaryEncodePass(t, `["null","int","string"]`, goavro.UnionValue{Name: "int", Value: 3}, []byte{2, 6})
+	testTextEncodePass(t, `["null","int","string"]`, goavro.UnionValue{Name: "int", Value: 3}, []byte(`{"int":3}`))
+	testBinaryEncodeFail(t, `["null","int"]`, goavro.UnionValue{Name: "string", Value: "abc"}, `cannot encode binary union: no member schema types support datum: allowed types: [null int]; received: "string"`)
+}
+
+func TestUnionValuesProjection(t *testing.T) {
+	codec, err := goavro.NewProjectionCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":["null",{"type":"record","name":"r2","fields":[{"name":"a","type":"int"},{"name":"b","type":"int"}]}]}]}`,
+		[]string{"/f1/a"}, goavro.WithUnionValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _, err := codec.NativeFromBinary([]byte{2, 2, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"f1": goavro.UnionValue{Index: 1, Name: "r2", Value: map[string]interface{}{"a": int32(1)}}}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", decoded, expected)
+	}
+}
@@ -0,0 +1,148 @@
+package goavro
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ValidationError describes one violation found by Codec.Validate.
+type ValidationError struct {
+	// Path is the JSON pointer of the invalid datum, using the same syntax as
+	// EncodeError.
+	Path string
+
+	// Expected is the Avro type of the invalid datum, for instance "long",
+	// "array", or the full name of a named type.
+	Expected string
+
+	// Received is the Go type of the invalid datum, or the empty string when
+	// a required record field is missing.
+	Received string
+
+	// Message describes the violation.
+	Message string
+}
+
+// Error returns the path of the invalid datum followed by the description of
+// the violation.
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Validate returns every violation found in the provided native datum that
+// would cause BinaryFromNative to fail, or nil when the datum is valid. Unlike
+// BinaryFromNative, it does not stop at the first violation, and does not
+// encode the datum. Violations are reported in record field declaration order,
+// array item order, and sorted map key order. When the Codec was created with
+// the WithStrictFields option, unknown keys of each record are reported after
+// its fields, in sorted order. The items of arrays and the entries of maps
+// provided by a producer, such as an ItemIterator, are not validated, because
+// doing so would consume them.
+//
+//     for _, violation := range codec.Validate(datum) {
+//         fmt.Println(violation)
+//     }
+func (c *Codec) Validate(datum interface{}) []ValidationError {
+	return validate(c.node, "", datum, nil)
+}
+
+// validate appends to violations each violation found in datum, described by
+// schema s, and located at path.
+func validate(s Schema, path string, datum interface{}, violations []ValidationError) []ValidationError {
+	switch v := s.(type) {
+	case *RecordSchema:
+		recordMap, ok := datum.(map[string]interface{})
+		if !ok {
+			return appendViolation(violations, s, path, datum, fmt.Sprintf("expected map[string]interface{}; received: %T", datum))
+		}
+		for _, field := range v.fields {
+			fieldPath := path + "/" + escapePathSegment(field.name)
+			value, ok := recordMap[field.name]
+			if !ok {
+				if !field.hasDefault {
+					violations = append(violations, ValidationError{
+						Path:     fieldPath,
+						Expected: field.schema.codec().typeName.fullName,
+						Message:  fmt.Sprintf("record %q field %q: schema does not specify default value and no value provided", v.Name(), field.name),
+					})
+				}
+				continue
+			}
+			violations = validate(field.schema, fieldPath, value, violations)
+		}
+		if v.c.config.strictFields {
+			var unknown []string
+			for key := range recordMap {
+				if _, ok := v.Field(key); !ok {
+					unknown = append(unknown, key)
+				}
+			}
+			sort.Strings(unknown)
+			for _, key := range unknown {
+				violations = append(violations, ValidationError{
+					Path:     path + "/" + escapePathSegment(key),
+					Received: fmt.Sprintf("%T", recordMap[key]),
+					Message:  fmt.Sprintf("record %q has no field %q", v.Name(), key),
+				})
+			}
+		}
+		return violations
+	case *ArraySchema:
+		if _, ok := itemProducer(datum); ok {
+			return violations // items cannot be validated without consuming them
+		}
+		arrayValues, err := convertArray(datum)
+		if err != nil {
+			return appendViolation(violations, s, path, datum, err.Error())
+		}
+		for i, item := range arrayValues {
+			violations = validate(v.items, path+"/"+strconv.Itoa(i), item, violations)
+		}
+		return violations
+	case *MapSchema:
+		if _, ok := entryProducer(datum); ok {
+			return violations // entries cannot be validated without consuming them
+		}
+		mapValues, err := convertMap(datum)
+		if err != nil {
+			return appendViolation(violations, s, path, datum, err.Error())
+		}
+		keys := make([]string, 0, len(mapValues))
+		for key := range mapValues {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			violations = validate(v.values, path+"/"+escapePathSegment(key), mapValues[key], violations)
+		}
+		return violations
+	case *UnionSchema:
+		index, value, err := v.memberOf(datum)
+		if err != nil {
+			return appendViolation(violations, s, path, datum, err.Error())
+		}
+		if datum == nil {
+			return violations
+		}
+		return validate(v.members[index], path+"/"+escapePathSegment(v.members[index].codec().typeName.fullName), value, violations)
+	}
+	// NOTE: All other types have no children, so their encoders report their
+	// violations.
+	if _, err := s.codec().binaryFromNative(nil, datum); err != nil {
+		return appendViolation(violations, s, path, datum, err.Error())
+	}
+	return violations
+}
+
+func appendViolation(violations []ValidationError, s Schema, path string, datum interface{}, message string) []ValidationError {
+	return append(violations, ValidationError{
+		Path:     path,
+		Expected: s.codec().typeName.fullName,
+		Received: fmt.Sprintf("%T", datum),
+		Message:  message,
+	})
+}
@@ -0,0 +1,103 @@
+package goavro_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+const validateTestSchema = `
+{"type": "record", "name": "Account",
+ "fields": [
+     {"name": "id", "type": "int"},
+     {"name": "email", "type": "string"},
+     {"name": "nickname", "type": ["null", "string"], "default": null},
+     {"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["ACTIVE", "CLOSED"]}},
+     {"name": "key", "type": {"type": "fixed", "name": "Key", "size": 4}},
+     {"name": "scores", "type": {"type": "array", "items": "double"}},
+     {"name": "limits", "type": {"type": "map", "values": "long"}}
+ ]
+}`
+
+func TestValidateValidDatum(t *testing.T) {
+	codec, err := goavro.NewCodec(validateTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations := codec.Validate(map[string]interface{}{
+		"id":     13,
+		"email":  "ann@example.com",
+		"status": "ACTIVE",
+		"key":    []byte("abcd"),
+		"scores": []float64{1.5, 2.5},
+		"limits": map[string]interface{}{"daily": int64(100)},
+	})
+	if violations != nil {
+		t.Errorf("Actual: %v; Expected: %v", violations, nil)
+	}
+}
+
+func TestValidateCollectsAllViolations(t *testing.T) {
+	codec, err := goavro.NewCodec(validateTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations := codec.Validate(map[string]interface{}{
+		"id":       int64(1) << 40,
+		"nickname": goavro.Union("string", 42),
+		"status":   "OPEN",
+		"key":      []byte("abc"),
+		"scores":   []interface{}{1.5, "two"},
+		"limits":   map[string]interface{}{"daily": "lots", "hourly": int64(10), "a/b": nil},
+	})
+
+	var paths []string
+	for _, violation := range violations {
+		paths = append(paths, violation.Path)
+	}
+	expected := []string{"/id", "/email", "/nickname/string", "/status", "/key", "/scores/1", "/limits/a~1b", "/limits/daily"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("Actual: %v; Expected: %v", paths, expected)
+	}
+
+	if actual, expected := violations[1], (goavro.ValidationError{Path: "/email", Expected: "string", Message: `record "Account" field "email": schema does not specify default value and no value provided`}); actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := violations[5].Expected, "double"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := violations[5].Received, "string"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	ensureError(t, violations[0], "/id: cannot encode binary int: provided Go int64 would lose precision")
+	ensureError(t, violations[3], "value ought to be member of symbols")
+	ensureError(t, violations[4], "datum size ought to equal schema size")
+}
+
+func TestValidateWrongContainerTypes(t *testing.T) {
+	codec, err := goavro.NewCodec(validateTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations := codec.Validate("not a record")
+	if len(violations) != 1 {
+		t.Fatalf("Actual: %v; Expected: %v", len(violations), 1)
+	}
+	if actual, expected := violations[0].Expected, "Account"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	codec, err = goavro.NewCodec(`["null", "int"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if violations = codec.Validate(nil); violations != nil {
+		t.Errorf("Actual: %v; Expected: %v", violations, nil)
+	}
+	violations = codec.Validate(goavro.Union("string", "x"))
+	if len(violations) != 1 {
+		t.Fatalf("Actual: %v; Expected: %v", len(violations), 1)
+	}
+	ensureError(t, violations[0], "no member schema types support datum")
+}